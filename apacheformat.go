@@ -0,0 +1,422 @@
+package goolog2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Apache common logging format
+const ApacheFormatCommon = `%h %l %u %t "%r" %>s %b`
+
+// Apache combined logging format
+const ApacheFormatCombined = `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"`
+
+// Apache logging object carrying details of the request
+//
+// The formatted Apache logger (see NewApacheFormatLogger) needs this
+// interface for the duration and header directives. Objects implementing
+// only the ApacheObject interface can be logged too: the header directives
+// of the referer and the user agent are taken from GetValues and other
+// missing values are logged as "-".
+type ApacheRequestObject interface {
+	ApacheObject
+
+	// Get duration of processing of the request
+	GetDuration() time.Duration
+
+	// Get value of a request header
+	//
+	// Parameters:
+	//     name: name of the header (case insensitive)
+	// Returns:
+	//     the value or an empty string
+	GetRequestHeader(
+		name string) string
+
+	// Get value of a response header
+	//
+	// Parameters:
+	//     name: name of the header (case insensitive)
+	// Returns:
+	//     the value or an empty string
+	GetResponseHeader(
+		name string) string
+}
+
+// Values of one logged object
+type apacheRecord struct {
+	remoteHost  string
+	identity    string
+	user        string
+	requestTime time.Time
+	method      string
+	resource    string
+	protocol    string
+	status      int
+	length      uint64
+	referer     string
+	agent       string
+	request     ApacheRequestObject
+}
+
+// One compiled directive of the format
+type apacheDirective func(builder *strings.Builder, record *apacheRecord)
+
+type apacheFormatLogger struct {
+	file       FileHolder
+	directives []apacheDirective
+}
+
+// Create new Apache logger with a custom format
+//
+// The format follows the Apache mod_log_config. Supported directives:
+//     %% ............ the percent sign
+//     %a, %h ........ remote host
+//     %l ............ remote identity
+//     %u ............ remote user
+//     %t ............ time of the request in the common log format
+//     %{sec}t, %{msec}t, %{usec}t
+//                     time of the request since the epoch
+//     %r ............ first line of the request
+//     %m ............ request method
+//     %U ............ requested URL path without the query string
+//     %q ............ query string (prepended by ?) or an empty string
+//     %H ............ request protocol
+//     %s, %>s ....... status
+//     %b ............ size of the response body, "-" if it's zero
+//     %B ............ size of the response body
+//     %D ............ duration of the request in microseconds
+//     %T ............ duration of the request in seconds
+//     %{ms}T, %{us}T, %{s}T
+//                     duration of the request in the unit
+//     %{Name}i ...... request header
+//     %{Name}o ...... response header
+// The format is compiled once. Values controlled by the clients are
+// escaped like Apache does.
+//
+// Parameters:
+//     file: a file holder used for the output
+//     format: the format (see ApacheFormatCommon and ApacheFormatCombined)
+// Returns:
+//     the logger
+//     an error if the format is invalid
+func NewApacheFormatLogger(
+	file FileHolder,
+	format string,
+) (Logger, error) {
+	directives, err := compileApacheFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return &apacheFormatLogger{
+		file:       file.Ref(),
+		directives: directives,
+	}, nil
+}
+
+func (this *apacheFormatLogger) Destroy() {
+	this.file.Unref()
+}
+
+func (this *apacheFormatLogger) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	if reporter, ok := this.file.(ErrorReporter); ok {
+		reporter.SetErrorHandler(handler)
+	}
+}
+
+func (this *apacheFormatLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	/* -- the logger supports only apache objects */
+	apache, ok := object.(ApacheObject)
+	if !ok {
+		return
+	}
+
+	record := &apacheRecord{}
+	record.remoteHost, record.identity, record.user, record.requestTime,
+		record.method, record.resource, record.protocol, record.status,
+		record.length, record.referer, record.agent = apache.GetValues()
+	record.request, _ = object.(ApacheRequestObject)
+
+	var builder strings.Builder
+	for _, directive := range this.directives {
+		directive(&builder, record)
+	}
+	builder.WriteByte('\n')
+
+	/* -- write the message */
+	this.file.AccessWriter(func(writer FileWriter) {
+		writer.Write([]byte(builder.String()))
+	})
+}
+
+// Compile an Apache logging format
+func compileApacheFormat(
+	format string,
+) ([]apacheDirective, error) {
+	var directives []apacheDirective
+	literal := ""
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			literal += format[i : i+1]
+			continue
+		}
+
+		/* -- parse the directive: %[>|<][{argument}]letter */
+		start := i
+		i++
+		if i < len(format) && (format[i] == '>' || format[i] == '<') {
+			i++
+		}
+		argument := ""
+		if i < len(format) && format[i] == '{' {
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated argument of the directive at %d", start)
+			}
+			argument = format[i+1 : i+end]
+			i += end + 1
+		}
+		if i >= len(format) {
+			return nil, fmt.Errorf("incomplete directive at %d", start)
+		}
+		if format[i] == '%' {
+			literal += "%"
+			continue
+		}
+
+		directive, err := newApacheDirective(format[i], argument)
+		if err != nil {
+			return nil, fmt.Errorf("directive %s: %s", format[start:i+1], err)
+		}
+		if literal != "" {
+			directives = append(directives, apacheLiteral(literal))
+			literal = ""
+		}
+		directives = append(directives, directive)
+	}
+	if literal != "" {
+		directives = append(directives, apacheLiteral(literal))
+	}
+	return directives, nil
+}
+
+func apacheLiteral(
+	text string,
+) apacheDirective {
+	return func(builder *strings.Builder, record *apacheRecord) {
+		builder.WriteString(text)
+	}
+}
+
+// Create one directive
+func newApacheDirective(
+	letter byte,
+	argument string,
+) (apacheDirective, error) {
+	switch letter {
+	case 'a', 'h':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.remoteHost)
+		}, nil
+	case 'l':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.identity)
+		}, nil
+	case 'u':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.user)
+		}, nil
+	case 't':
+		return newApacheTimeDirective(argument)
+	case 'r':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(
+				builder, record.method+" "+record.resource+" "+record.protocol)
+		}, nil
+	case 'm':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.method)
+		}, nil
+	case 'U':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			path := record.resource
+			if index := strings.IndexByte(path, '?'); index >= 0 {
+				path = path[:index]
+			}
+			writeApacheValue(builder, path)
+		}, nil
+	case 'q':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			if index := strings.IndexByte(record.resource, '?'); index >= 0 {
+				builder.WriteString(escapeApacheValue(record.resource[index:]))
+			}
+		}, nil
+	case 'H':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.protocol)
+		}, nil
+	case 's':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			fmt.Fprintf(builder, "%03d", record.status)
+		}, nil
+	case 'b':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			if record.length == 0 {
+				builder.WriteByte('-')
+			} else {
+				builder.WriteString(strconv.FormatUint(record.length, 10))
+			}
+		}, nil
+	case 'B':
+		return func(builder *strings.Builder, record *apacheRecord) {
+			builder.WriteString(strconv.FormatUint(record.length, 10))
+		}, nil
+	case 'D':
+		return newApacheDurationDirective(time.Microsecond), nil
+	case 'T':
+		switch argument {
+		case "", "s":
+			return newApacheDurationDirective(time.Second), nil
+		case "ms":
+			return newApacheDurationDirective(time.Millisecond), nil
+		case "us":
+			return newApacheDurationDirective(time.Microsecond), nil
+		default:
+			return nil, fmt.Errorf("unknown unit %q", argument)
+		}
+	case 'i':
+		if argument == "" {
+			return nil, fmt.Errorf("missing header name")
+		}
+		return func(builder *strings.Builder, record *apacheRecord) {
+			writeApacheValue(builder, record.requestHeader(argument))
+		}, nil
+	case 'o':
+		if argument == "" {
+			return nil, fmt.Errorf("missing header name")
+		}
+		return func(builder *strings.Builder, record *apacheRecord) {
+			value := ""
+			if record.request != nil {
+				value = record.request.GetResponseHeader(argument)
+			}
+			writeApacheValue(builder, value)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported directive")
+	}
+}
+
+func newApacheTimeDirective(
+	argument string,
+) (apacheDirective, error) {
+	switch argument {
+	case "":
+		return func(builder *strings.Builder, record *apacheRecord) {
+			builder.WriteString(record.requestTime.Format("[02/Jan/2006:15:04:05 -0700]"))
+		}, nil
+	case "sec":
+		return func(builder *strings.Builder, record *apacheRecord) {
+			builder.WriteString(strconv.FormatInt(record.requestTime.Unix(), 10))
+		}, nil
+	case "msec":
+		return func(builder *strings.Builder, record *apacheRecord) {
+			builder.WriteString(strconv.FormatInt(record.requestTime.UnixNano()/int64(time.Millisecond), 10))
+		}, nil
+	case "usec":
+		return func(builder *strings.Builder, record *apacheRecord) {
+			builder.WriteString(strconv.FormatInt(record.requestTime.UnixNano()/int64(time.Microsecond), 10))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported time format %q", argument)
+	}
+}
+
+func newApacheDurationDirective(
+	unit time.Duration,
+) apacheDirective {
+	return func(builder *strings.Builder, record *apacheRecord) {
+		if record.request == nil {
+			builder.WriteByte('-')
+			return
+		}
+		builder.WriteString(strconv.FormatInt(int64(record.request.GetDuration()/unit), 10))
+	}
+}
+
+// Get a request header
+//
+// The referer and the user agent of simple apache objects are taken
+// from their values.
+func (this *apacheRecord) requestHeader(
+	name string,
+) string {
+	if this.request != nil {
+		return this.request.GetRequestHeader(name)
+	}
+	switch strings.ToLower(name) {
+	case "referer":
+		return this.referer
+	case "user-agent":
+		return this.agent
+	default:
+		return ""
+	}
+}
+
+// Write a value, an empty value is written as "-"
+func writeApacheValue(
+	builder *strings.Builder,
+	value string,
+) {
+	if value == "" {
+		builder.WriteByte('-')
+		return
+	}
+	builder.WriteString(escapeApacheValue(value))
+}
+
+// Escape quotes, backslashes and non-printable characters
+func escapeApacheValue(
+	value string,
+) string {
+	escape := false
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < 0x20 || c >= 0x7f || c == '"' || c == '\\' {
+			escape = true
+			break
+		}
+	}
+	if !escape {
+		return value
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(c)
+		case c == '\n':
+			builder.WriteString(`\n`)
+		case c == '\t':
+			builder.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&builder, `\x%02x`, c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,106 @@
+package goolog2_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestApacheFormatErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%Z", "directive %Z: unsupported directive"},
+		{"%{User-agent", "unterminated argument of the directive at 0"},
+		{"%h %", "incomplete directive at 3"},
+		{"%i", "directive %i: missing header name"},
+		{"%{min}T", `directive %{min}T: unknown unit "min"`},
+		{"%{%Y}t", `directive %{%Y}t: unsupported time format "%Y"`},
+	}
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	holder := NewSimpleFile(filepath.Join(dir, "access.log"), false)
+	defer holder.Unref()
+	for _, test := range tests {
+		_, err := NewApacheFormatLogger(holder, test.format)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: unexpected error: %v", test.format, err)
+		}
+	}
+}
+
+func TestApacheFormatLogger(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	formats := []string{ApacheFormatCombined, "%B %b %D %{Referer}i %{X-Custom}o %q%% %{sec}t"}
+	dispatcher := NewLogDispatcher("testlog")
+	for i, format := range formats {
+		holder := NewSimpleFile(filepath.Join(dir, fmt.Sprintf("access%d.log", i)), false)
+		logger, err := NewApacheFormatLogger(holder, format)
+		holder.Unref()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		dispatcher.AddLogger(fmt.Sprintf("access%d", i), "", MaskAll, 5, logger)
+	}
+
+	/* -- a simple apache object */
+	now, _ := time.Parse("2006-01-02T15:04:05 -0700 MST", "2018-08-29T22:16:26 +0200 CEST")
+	DispatcherLogObject(dispatcher, "", Info, 1, &mockApacheObject{
+		remoteHost:  "127.0.0.1",
+		requestTime: now,
+		method:      "GET",
+		resource:    "/sws/my_resource.json?id=1",
+		protocol:    "HTTP/1.0",
+		status:      200,
+		referer:     "http://www.google.com/",
+		agent:       `Chrome "1.0"`,
+	})
+
+	/* -- the request object of the middleware */
+	middleware, _ := NewAccessLogMiddleware(
+		dispatcher, AccessLogOptions{TimeSource: NewMockTimeSource(now)})
+	handler := middleware(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("X-Custom", "custom value")
+			writer.Write([]byte("body"))
+		}))
+	request := httptest.NewRequest("POST", "/submit", nil)
+	request.RemoteAddr = "10.0.0.1:1234"
+	request.Header.Set("Referer", "http://example.com/form")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	dispatcher.Destroy()
+
+	expected := []string{
+		`127.0.0.1 - - [29/Aug/2018:22:16:26 +0200] "GET /sws/my_resource.json?id=1 HTTP/1.0" 200 - "http://www.google.com/" "Chrome \"1.0\""
+10.0.0.1 - - [29/Aug/2018:22:16:26 +0200] "POST /submit HTTP/1.1" 200 4 "http://example.com/form" "-"
+`,
+		`0 - - http://www.google.com/ - ?id=1% 1535573786
+4 4 0 http://example.com/form custom value % 1535573786
+`,
+	}
+	for i := range formats {
+		content, err := ioutil.ReadFile(filepath.Join(dir, fmt.Sprintf("access%d.log", i)))
+		if err != nil || string(content) != expected[i] {
+			t.Errorf("unexpected log %d: %q", i, content)
+		}
+	}
+}
@@ -29,6 +29,14 @@ func (this *apacheLogger) Destroy() {
 	this.file.Unref()
 }
 
+func (this *apacheLogger) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	if reporter, ok := this.file.(ErrorReporter); ok {
+		reporter.SetErrorHandler(handler)
+	}
+}
+
 func (this *apacheLogger) LogObject(
 	system string,
 	subsystem Subsystem,
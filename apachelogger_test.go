@@ -54,9 +54,7 @@ func TestApacheLogger(t *testing.T) {
 	os.Remove(logfile)
 
 	now, _ := time.Parse("2006-01-02T15:04:05 -0700 MST", "2018-08-29T22:16:26 +0200 CEST")
-	timesrc := &mockTimeSource{
-		now: now,
-	}
+	timesrc := NewMockTimeSource(now)
 
 	InitWithTimeSource("testlog", timesrc)
 	AddApacheLogger("apache", "", MaskAll, 5, logfile, false)
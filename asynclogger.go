@@ -0,0 +1,271 @@
+package goolog2
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Overflow policy of the asynchronous logger
+type AsyncPolicy int
+
+const (
+	// Block the logging goroutine until there is a room in the queue
+	AsyncBlock AsyncPolicy = iota
+	// Drop the message if the queue is full
+	AsyncDropNewest
+	// Drop less severe messages earlier: debug messages are dropped
+	// if the queue is half full, info messages if it's three quarters
+	// full, warnings and errors if it's full. Critical messages are never
+	// dropped - the logging goroutine is blocked instead.
+	AsyncDropBySeverity
+)
+
+// Asynchronous logger
+//
+// The logger passes the logged objects through a bounded queue to
+// a background goroutine which invokes the wrapped logger.
+type AsyncLogger interface {
+	Logger
+
+	// Wait until all messages queued before the call are logged
+	//
+	// Parameters:
+	//     timeout: maximal time of waiting. Zero or negative value means
+	//         no limit.
+	// Returns:
+	//     false if the timeout has expired
+	Flush(
+		timeout time.Duration) bool
+
+	// Get number of dropped messages
+	Dropped() uint64
+}
+
+// Logging object referring data owned by the caller
+//
+// The asynchronous logger detaches such objects before they're queued.
+type detachableObject interface {
+	// Get a copy of the object independent of the caller's data
+	detachLogObject() interface{}
+}
+
+// Logger stamping the records by a time source
+//
+// The asynchronous logger takes the time when a message is queued,
+// hence the records keep the time of the event.
+type timedLogger interface {
+	// Get current time of the logger's time source
+	loggerNow() time.Time
+
+	// Log an object with a time taken earlier
+	logObjectAt(
+		now time.Time,
+		system string,
+		subsystem Subsystem,
+		severity Severity,
+		verbosity Verbosity,
+		object interface{})
+}
+
+type asyncItem struct {
+	now       time.Time
+	system    string
+	subsystem Subsystem
+	severity  Severity
+	verbosity Verbosity
+	object    interface{}
+	flushed   chan struct{}
+}
+
+type asyncLogger struct {
+	logger  Logger
+	timed   timedLogger
+	policy  AsyncPolicy
+	queue   chan asyncItem
+	done    chan struct{}
+	dropped uint64
+}
+
+// Create new asynchronous logger
+//
+// Parameters:
+//     logger: the wrapped logger. The ownership is taken - the logger
+//         is destroyed with the asynchronous logger.
+//     queueSize: capacity of the queue
+//     policy: behavior if the queue is full
+// Returns:
+//     the logger
+func NewAsyncLogger(
+	logger Logger,
+	queueSize int,
+	policy AsyncPolicy,
+) AsyncLogger {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	async := &asyncLogger{
+		logger: logger,
+		policy: policy,
+		queue:  make(chan asyncItem, queueSize),
+		done:   make(chan struct{}),
+	}
+	async.timed, _ = logger.(timedLogger)
+	go async.mainThread()
+	return async
+}
+
+// Destroy the logger
+//
+// All queued messages are flushed before the wrapped logger is destroyed.
+func (this *asyncLogger) Destroy() {
+	this.Flush(0)
+	close(this.queue)
+	<-this.done
+	this.logger.Destroy()
+}
+
+func (this *asyncLogger) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	if reporter, ok := this.logger.(ErrorReporter); ok {
+		reporter.SetErrorHandler(handler)
+	}
+}
+
+func (this *asyncLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	/* -- the caller can change its data after the call returns */
+	if detachable, ok := object.(detachableObject); ok {
+		object = detachable.detachLogObject()
+	}
+	item := asyncItem{
+		system:    system,
+		subsystem: subsystem,
+		severity:  severity,
+		verbosity: verbosity,
+		object:    object,
+	}
+	if this.timed != nil {
+		item.now = this.timed.loggerNow()
+	}
+
+	switch this.policy {
+	case AsyncDropNewest:
+		this.tryEnqueue(item)
+	case AsyncDropBySeverity:
+		if severity == Critical {
+			this.queue <- item
+			return
+		}
+		if len(this.queue) >= this.severityLimit(severity) {
+			atomic.AddUint64(&this.dropped, 1)
+			return
+		}
+		this.tryEnqueue(item)
+	default:
+		this.queue <- item
+	}
+}
+
+func (this *asyncLogger) tryEnqueue(
+	item asyncItem,
+) {
+	select {
+	case this.queue <- item:
+	default:
+		atomic.AddUint64(&this.dropped, 1)
+	}
+}
+
+// Get the queue length at which messages of the severity are dropped
+func (this *asyncLogger) severityLimit(
+	severity Severity,
+) int {
+	capacity := cap(this.queue)
+	switch severity {
+	case Debug:
+		return (capacity + 1) / 2
+	case Info:
+		return (capacity*3 + 3) / 4
+	default:
+		return capacity
+	}
+}
+
+func (this *asyncLogger) Flush(
+	timeout time.Duration,
+) bool {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	/* -- the marker is processed after all messages queued before it */
+	flushed := make(chan struct{})
+	select {
+	case this.queue <- asyncItem{flushed: flushed}:
+	case <-expired:
+		return false
+	}
+	select {
+	case <-flushed:
+		return true
+	case <-expired:
+		return false
+	}
+}
+
+func (this *asyncLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&this.dropped)
+}
+
+func (this *asyncLogger) mainThread() {
+	defer close(this.done)
+	for item := range this.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if this.timed != nil {
+			this.timed.logObjectAt(
+				item.now, item.system, item.subsystem, item.severity, item.verbosity,
+				item.object)
+		} else {
+			this.logger.LogObject(
+				item.system, item.subsystem, item.severity, item.verbosity, item.object)
+		}
+	}
+}
+
+func (this *formattedLogMessageObject) detachLogObject() interface{} {
+	return &simpleLogMessageObject{this.GetLogLine()}
+}
+
+func (this *lazyLogMessageObject) detachLogObject() interface{} {
+	return &simpleLogMessageObject{this.GetLogLine()}
+}
+
+func (this *fieldsMessageObject) detachLogObject() interface{} {
+	fields := make([]Field, len(this.fields))
+	for i, field := range this.fields {
+		fields[i].Key = field.Key
+		switch field.Value.(type) {
+		case nil, string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64,
+			time.Duration, time.Time:
+			fields[i].Value = field.Value
+		default:
+			/* -- the value can refer the caller's data */
+			fields[i].Value = fmt.Sprint(field.Value)
+		}
+	}
+	return &fieldsMessageObject{this.message, fields}
+}
@@ -0,0 +1,197 @@
+package goolog2_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+type gatedLogger struct {
+	gate      chan struct{}
+	entered   chan struct{}
+	mutex     sync.Mutex
+	lines     []string
+	objects   []interface{}
+	destroyed bool
+}
+
+func newGatedLogger() *gatedLogger {
+	return &gatedLogger{
+		gate:    make(chan struct{}),
+		entered: make(chan struct{}, 100),
+	}
+}
+
+func (this *gatedLogger) Destroy() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.destroyed = true
+}
+
+func (this *gatedLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	this.entered <- struct{}{}
+	<-this.gate
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.lines = append(this.lines, object.(LineObject).GetLogLine())
+	this.objects = append(this.objects, object)
+}
+
+func (this *gatedLogger) Lines() []string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]string(nil), this.lines...)
+}
+
+func TestAsyncLoggerDropNewest(t *testing.T) {
+	inner := newGatedLogger()
+	logger := NewAsyncLogger(inner, 2, AsyncDropNewest)
+
+	/* -- the first message is taken by the background goroutine, two
+	   wait in the queue, the rest is dropped */
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"1"})
+	<-inner.entered
+	for i := 2; i <= 6; i++ {
+		logger.LogObject("testlog", "", Critical, 1, &lineObject{"x"})
+	}
+	if logger.Dropped() != 3 {
+		t.Errorf("unexpected number of dropped messages: %d", logger.Dropped())
+	}
+	if logger.Flush(10 * time.Millisecond) {
+		t.Errorf("the flush shouldn't succeed while the logger is blocked")
+	}
+
+	close(inner.gate)
+	if !logger.Flush(time.Second) {
+		t.Errorf("the flush should succeed")
+	}
+	if lines := inner.Lines(); len(lines) != 3 {
+		t.Errorf("unexpected logged lines: %v", lines)
+	}
+	logger.Destroy()
+	if !inner.destroyed {
+		t.Errorf("the wrapped logger should be destroyed")
+	}
+}
+
+func TestAsyncLoggerDropBySeverity(t *testing.T) {
+	inner := newGatedLogger()
+	logger := NewAsyncLogger(inner, 4, AsyncDropBySeverity)
+
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"taken"})
+	<-inner.entered
+
+	/* -- the queue is empty now */
+	logger.LogObject("testlog", "", Debug, 1, &lineObject{"debug 1"})
+	logger.LogObject("testlog", "", Debug, 1, &lineObject{"debug 2"})
+	logger.LogObject("testlog", "", Debug, 1, &lineObject{"dropped"})
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"info"})
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"dropped"})
+	logger.LogObject("testlog", "", Error, 1, &lineObject{"error"})
+	logger.LogObject("testlog", "", Error, 1, &lineObject{"dropped"})
+	if logger.Dropped() != 3 {
+		t.Errorf("unexpected number of dropped messages: %d", logger.Dropped())
+	}
+
+	/* -- critical messages block instead of dropping */
+	done := make(chan struct{})
+	go func() {
+		logger.LogObject("testlog", "", Critical, 1, &lineObject{"critical"})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Errorf("the critical message should block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(inner.gate)
+	<-done
+
+	/* -- destroying flushes the queue */
+	logger.Destroy()
+	expected := []string{"taken", "debug 1", "debug 2", "info", "error", "critical"}
+	lines := inner.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected logged lines: %v", lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("unexpected logged lines: %v", lines)
+			break
+		}
+	}
+}
+
+func TestAsyncLoggerDetaching(t *testing.T) {
+	inner := newGatedLogger()
+	dispatcher := NewLogDispatcher("testlog")
+	dispatcher.AddLogger("async", "", MaskAll, 5, NewAsyncLogger(inner, 10, AsyncBlock))
+
+	/* -- the arguments are changed before the message is logged */
+	data := []int{1, 2}
+	DispatcherLogMessagef(dispatcher, "", Info, 1, "data %v", data)
+	DispatcherLogMessagekv(dispatcher, "", Info, 1, "fields", "data", data)
+	data[0] = 42
+	close(inner.gate)
+	dispatcher.Destroy()
+
+	lines := inner.Lines()
+	if len(lines) != 2 || lines[0] != "data [1 2]" {
+		t.Errorf("unexpected logged lines: %v", lines)
+	}
+	fields := inner.objects[1].(FieldsObject).GetLogFields()
+	if len(fields) != 1 || fields[0].Value != "[1 2]" {
+		t.Errorf("unexpected logged fields: %v", fields)
+	}
+}
+
+/* -- file holder blocking the writes until the gate is opened */
+type gatedFile struct {
+	gate   chan struct{}
+	writer bufferWriter
+}
+
+func (this *gatedFile) AccessWriter(
+	functor func(writer FileWriter),
+) {
+	<-this.gate
+	functor(&this.writer)
+}
+
+func (this *gatedFile) Ref() FileHolder {
+	return this
+}
+
+func (this *gatedFile) Unref() {
+}
+
+func TestAsyncLoggerEventTime(t *testing.T) {
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:27")
+	timesrc := NewMockTimeSource(now)
+	file := &gatedFile{gate: make(chan struct{})}
+	logger := NewAsyncLogger(
+		NewFileLogger(timesrc, file, NewLineFormatterDefault(false)), 10, AsyncBlock)
+
+	/* -- the messages wait in the queue while the time goes on */
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"first"})
+	timesrc.Advance(time.Minute)
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"second"})
+	timesrc.Advance(time.Minute)
+	close(file.gate)
+	logger.Destroy()
+
+	expected := "testlog 2018-08-25T14:02:27 [    INFO, 1] (): first\n" +
+		"testlog 2018-08-25T14:03:27 [    INFO, 1] (): second\n"
+	if content := file.writer.String(); content != expected {
+		t.Errorf("unexpected content:\n%s", strings.TrimSpace(content))
+	}
+}
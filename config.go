@@ -0,0 +1,782 @@
+package goolog2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+// Configuration of the global log
+//
+// The configuration is usually loaded from a JSON file:
+//
+//     {
+//       "system": "myservice",
+//       "loggers": [
+//         {
+//           "name": "main",
+//           "type": "rotatable",
+//           "severities": "std",
+//           "verbosity": 3,
+//           "path": "/var/log/myservice.log",
+//           "rotation": {"max_size": 10485760, "check_interval": "5m"},
+//           "retention": {"max_generations": 10}
+//         },
+//         {
+//           "name": "console",
+//           "type": "console",
+//           "severities": "critical,error",
+//           "verbosity": 1,
+//           "output": "stderr"
+//         }
+//       ]
+//     }
+//
+// Durations are written in the format of time.ParseDuration ("90s", "1h").
+type Config struct {
+	// System identifier shown in the logs
+	System string `json:"system"`
+	// The loggers
+	Loggers []LoggerConfig `json:"loggers"`
+}
+
+// Configuration of one logger
+type LoggerConfig struct {
+	// Unique name of the logger
+	Name string `json:"name"`
+	// Type of the logger: "file", "rotatable", "pattern", "console",
+	// "apache" or "syslog"
+	Type string `json:"type"`
+	// Logging subsystem. Empty means all subsystems.
+	Subsystem string `json:"subsystem,omitempty"`
+	// Logged severities: "all", "std" or a comma separated list of
+	// severities ("critical,error,warning"). Empty means all.
+	Severities string `json:"severities,omitempty"`
+	// Maximal logged verbosity
+	Verbosity int `json:"verbosity"`
+	// Path of the logging file (file, rotatable and apache loggers)
+	Path string `json:"path,omitempty"`
+	// Pattern of the logging files (pattern and apache loggers).
+	// See NewPatternFile.
+	Pattern string `json:"pattern,omitempty"`
+	// Flush the file after every message
+	Sync bool `json:"sync,omitempty"`
+	// Line formatter: "default" or "json" (file, rotatable, pattern
+	// and console loggers)
+	Formatter string `json:"formatter,omitempty"`
+	// Output of the console logger: "stdout" (default) or "stderr"
+	Output string `json:"output,omitempty"`
+	// Format of the apache loggers (see NewApacheFormatLogger). Empty
+	// means the combined format.
+	LogFormat string `json:"log_format,omitempty"`
+	// Rotation of the file (rotatable and apache loggers)
+	Rotation *RotationConfig `json:"rotation,omitempty"`
+	// Retention of the rotated files (rotatable, pattern and apache loggers)
+	Retention *RetentionConfig `json:"retention,omitempty"`
+	// Connection to the syslog daemon (syslog loggers)
+	Syslog *SyslogConfig `json:"syslog,omitempty"`
+	// Asynchronous logging. If it's not set, the logger is synchronous.
+	Async *AsyncConfig `json:"async,omitempty"`
+	// Severities and verbosity overridden for subsystems
+	Overrides []OverrideConfig `json:"overrides,omitempty"`
+}
+
+// Configuration of the file rotation
+type RotationConfig struct {
+	// Rotate the file if it's bigger than the size (in bytes)
+	MaxSize int64 `json:"max_size,omitempty"`
+	// Interval of the size checks
+	CheckInterval string `json:"check_interval,omitempty"`
+	// Time-based rotation: "hourly", "daily" or "weekly"
+	Schedule string `json:"schedule,omitempty"`
+	// Offset of the scheduled rotation from the beginning of the period
+	At string `json:"at,omitempty"`
+	// Day of the weekly rotation ("sunday", "monday"...)
+	Weekday string `json:"weekday,omitempty"`
+	// Compression of the rotated files: "none" or "gzip"
+	Compression string `json:"compression,omitempty"`
+	// Compression level (see compress/gzip)
+	CompressionLevel int `json:"compression_level,omitempty"`
+}
+
+// Configuration of the retention of rotated files
+type RetentionConfig struct {
+	// Maximal number of kept rotated files
+	MaxGenerations int `json:"max_generations,omitempty"`
+	// Maximal age of kept rotated files
+	MaxAge string `json:"max_age,omitempty"`
+	// Maximal total size of the log files
+	MaxTotalBytes int64 `json:"max_total_bytes,omitempty"`
+}
+
+// Configuration of the syslog connection
+type SyslogConfig struct {
+	// "unixgram", "unix", "udp" or "tcp". Empty means the local syslog.
+	Network string `json:"network,omitempty"`
+	// Address of the syslog daemon
+	Address string `json:"address,omitempty"`
+	// Format of the messages: "rfc3164" (default) or "rfc5424"
+	Format string `json:"format,omitempty"`
+	// Syslog facility: "user" (default), "daemon", "local0"...
+	Facility string `json:"facility,omitempty"`
+}
+
+// Severities and verbosity of a logger overridden for a subsystem
+//
+// See LogDispatcher.SetLoggerSubsystemVerbosity.
+type OverrideConfig struct {
+	// The subsystem (including its nested subsystems)
+	Subsystem string `json:"subsystem"`
+	// Logged severities. Empty means the severities of the logger.
+	Severities string `json:"severities,omitempty"`
+	// Maximal logged verbosity
+	Verbosity int `json:"verbosity"`
+}
+
+// Configuration of the asynchronous logging
+type AsyncConfig struct {
+	// Capacity of the queue
+	QueueSize int `json:"queue_size"`
+	// Overflow policy: "block" (default), "drop-newest" or "drop-by-severity"
+	Policy string `json:"policy,omitempty"`
+}
+
+// Validated logger configuration
+type loggerSetup struct {
+	config     *LoggerConfig
+	severities SeverityMask
+	json       bool
+	rotatable  bool
+	rotation   RotatableFileOptions
+	pattern    PatternFileOptions
+	format     SyslogFormat
+	facility   SyslogFacility
+	policy     AsyncPolicy
+	overrides  []subsystemOverride
+}
+
+type subsystemOverride struct {
+	subsystem  Subsystem
+	severities SeverityMask
+	verbosity  Verbosity
+}
+
+var syslogFacilityNames = map[string]SyslogFacility{
+	"kern":     FacilityKern,
+	"user":     FacilityUser,
+	"mail":     FacilityMail,
+	"daemon":   FacilityDaemon,
+	"auth":     FacilityAuth,
+	"syslog":   FacilitySyslog,
+	"lpr":      FacilityLpr,
+	"news":     FacilityNews,
+	"uucp":     FacilityUucp,
+	"cron":     FacilityCron,
+	"authpriv": FacilityAuthPriv,
+	"ftp":      FacilityFtp,
+	"local0":   FacilityLocal0,
+	"local1":   FacilityLocal1,
+	"local2":   FacilityLocal2,
+	"local3":   FacilityLocal3,
+	"local4":   FacilityLocal4,
+	"local5":   FacilityLocal5,
+	"local6":   FacilityLocal6,
+	"local7":   FacilityLocal7,
+}
+
+// Load configuration from a JSON file
+//
+// Parameters:
+//     path: path of the configuration file
+// Returns:
+//     the validated configuration
+//     an error
+func LoadConfig(
+	path string,
+) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return config, nil
+}
+
+// Parse configuration in JSON
+//
+// Unknown keys are refused, hence typos don't pass silently.
+//
+// Parameters:
+//     data: the JSON document
+// Returns:
+//     the validated configuration
+//     an error
+func ParseConfig(
+	data []byte,
+) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %s", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate the configuration
+//
+// Returns:
+//     an error naming the first invalid logger
+func (this *Config) Validate() error {
+	_, err := this.resolve()
+	return err
+}
+
+func (this *Config) resolve() ([]*loggerSetup, error) {
+	if this.System == "" {
+		return nil, errors.New("missing system identifier")
+	}
+	names := make(map[string]bool)
+	setups := make([]*loggerSetup, 0, len(this.Loggers))
+	for i := range this.Loggers {
+		config := &this.Loggers[i]
+		if config.Name == "" {
+			return nil, fmt.Errorf("logger #%d: missing name", i+1)
+		}
+		if names[config.Name] {
+			return nil, fmt.Errorf("logger %q: duplicated name", config.Name)
+		}
+		names[config.Name] = true
+		setup, err := config.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("logger %q: %s", config.Name, err)
+		}
+		setups = append(setups, setup)
+	}
+	return setups, nil
+}
+
+func (this *LoggerConfig) resolve() (*loggerSetup, error) {
+	setup := &loggerSetup{config: this}
+
+	var err error
+	if setup.severities, err = ParseSeverityMask(this.Severities); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(this.Severities) == "" {
+		setup.severities = MaskAll
+	}
+	if this.Verbosity < 0 {
+		return nil, fmt.Errorf("negative verbosity %d", this.Verbosity)
+	}
+	switch this.Formatter {
+	case "", "default":
+	case "json":
+		setup.json = true
+	default:
+		return nil, fmt.Errorf("unknown formatter %q", this.Formatter)
+	}
+
+	/* -- check the fields required and allowed by the logger type */
+	var allowed []string
+	switch this.Type {
+	case "file":
+		err = requireConfigField("path", this.Path)
+		allowed = []string{"path", "formatter"}
+	case "rotatable":
+		err = requireConfigField("path", this.Path)
+		if err == nil && this.Rotation == nil {
+			err = errors.New("missing rotation")
+		}
+		setup.rotatable = true
+		allowed = []string{"path", "formatter", "rotation", "retention"}
+	case "pattern":
+		err = requireConfigField("pattern", this.Pattern)
+		allowed = []string{"pattern", "formatter", "retention"}
+	case "console":
+		switch this.Output {
+		case "", "stdout", "stderr":
+		default:
+			err = fmt.Errorf("unknown output %q", this.Output)
+		}
+		allowed = []string{"output", "formatter"}
+	case "apache":
+		switch {
+		case this.Path == "" && this.Pattern == "":
+			err = errors.New("missing path or pattern")
+		case this.Path != "" && this.Pattern != "":
+			err = errors.New("path and pattern are mutually exclusive")
+		case this.Pattern != "" && this.Rotation != nil:
+			err = errors.New("rotation of pattern files isn't supported")
+		}
+		if err == nil && this.LogFormat != "" {
+			if _, formatErr := compileApacheFormat(this.LogFormat); formatErr != nil {
+				err = fmt.Errorf("log_format: %s", formatErr)
+			}
+		}
+		setup.rotatable = this.Rotation != nil
+		allowed = []string{"path", "pattern", "rotation", "retention", "log_format"}
+	case "syslog":
+		allowed = []string{"syslog"}
+	case "":
+		err = errors.New("missing type")
+	default:
+		err = fmt.Errorf("unknown type %q", this.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := this.checkAllowedFields(allowed); err != nil {
+		return nil, err
+	}
+
+	if this.Rotation != nil {
+		if err := this.Rotation.resolve(&setup.rotation); err != nil {
+			return nil, fmt.Errorf("rotation: %s", err)
+		}
+	}
+	if this.Retention != nil {
+		if this.Type == "apache" && !setup.rotatable && this.Pattern == "" {
+			return nil, errors.New("retention requires rotation")
+		}
+		retention, err := this.Retention.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("retention: %s", err)
+		}
+		setup.rotation.Retention = retention
+		setup.pattern.Retention = retention
+	}
+	if this.Syslog != nil {
+		if err := this.Syslog.resolve(setup); err != nil {
+			return nil, fmt.Errorf("syslog: %s", err)
+		}
+	} else {
+		setup.facility = FacilityUser
+	}
+	for i := range this.Overrides {
+		override, err := this.Overrides[i].resolve(setup.severities)
+		if err != nil {
+			return nil, fmt.Errorf("override #%d: %s", i+1, err)
+		}
+		setup.overrides = append(setup.overrides, override)
+	}
+	if this.Async != nil {
+		if err := this.Async.resolve(setup); err != nil {
+			return nil, fmt.Errorf("async: %s", err)
+		}
+	}
+	return setup, nil
+}
+
+func requireConfigField(
+	name string,
+	value string,
+) error {
+	if value == "" {
+		return fmt.Errorf("missing %s", name)
+	}
+	return nil
+}
+
+// Refuse fields which make no sense for the logger type
+func (this *LoggerConfig) checkAllowedFields(
+	allowed []string,
+) error {
+	used := map[string]bool{
+		"path":       this.Path != "",
+		"pattern":    this.Pattern != "",
+		"formatter":  this.Formatter != "",
+		"output":     this.Output != "",
+		"rotation":   this.Rotation != nil,
+		"retention":  this.Retention != nil,
+		"syslog":     this.Syslog != nil,
+		"log_format": this.LogFormat != "",
+	}
+	for _, name := range allowed {
+		delete(used, name)
+	}
+	for _, name := range []string{
+		"path", "pattern", "formatter", "output", "rotation", "retention", "syslog",
+		"log_format",
+	} {
+		if used[name] {
+			return fmt.Errorf("%s isn't supported by %s loggers", name, this.Type)
+		}
+	}
+	return nil
+}
+
+func (this *RotationConfig) resolve(
+	options *RotatableFileOptions,
+) error {
+	var err error
+	if this.MaxSize < 0 {
+		return fmt.Errorf("negative max_size %d", this.MaxSize)
+	}
+	options.MaxSize = this.MaxSize
+	if options.CheckInterval, err = parseConfigDuration("check_interval", this.CheckInterval); err != nil {
+		return err
+	}
+	if options.MaxSize > 0 && options.CheckInterval == 0 {
+		return errors.New("max_size requires check_interval")
+	}
+
+	switch strings.ToLower(this.Schedule) {
+	case "":
+		options.Schedule.Period = RotateNever
+	case "hourly":
+		options.Schedule.Period = RotateHourly
+	case "daily":
+		options.Schedule.Period = RotateDaily
+	case "weekly":
+		options.Schedule.Period = RotateWeekly
+	default:
+		return fmt.Errorf("unknown schedule %q", this.Schedule)
+	}
+	if options.Schedule.At, err = parseConfigDuration("at", this.At); err != nil {
+		return err
+	}
+	if this.Weekday != "" {
+		if options.Schedule.Period != RotateWeekly {
+			return errors.New("weekday requires weekly schedule")
+		}
+		weekday, ok := parseConfigWeekday(this.Weekday)
+		if !ok {
+			return fmt.Errorf("unknown weekday %q", this.Weekday)
+		}
+		options.Schedule.Weekday = weekday
+	}
+	if options.MaxSize == 0 && options.Schedule.Period == RotateNever {
+		return errors.New("neither max_size nor schedule is set")
+	}
+
+	switch strings.ToLower(this.Compression) {
+	case "", "none":
+		options.Compression = CompressNone
+	case "gzip":
+		options.Compression = CompressGzip
+	default:
+		return fmt.Errorf("unknown compression %q", this.Compression)
+	}
+	if this.CompressionLevel < 0 || this.CompressionLevel > 9 {
+		return fmt.Errorf("invalid compression_level %d", this.CompressionLevel)
+	}
+	options.CompressionLevel = this.CompressionLevel
+	return nil
+}
+
+func (this *RetentionConfig) resolve() (RetentionPolicy, error) {
+	policy := RetentionPolicy{
+		MaxGenerations: this.MaxGenerations,
+		MaxTotalBytes:  this.MaxTotalBytes,
+	}
+	if this.MaxGenerations < 0 {
+		return policy, fmt.Errorf("negative max_generations %d", this.MaxGenerations)
+	}
+	if this.MaxTotalBytes < 0 {
+		return policy, fmt.Errorf("negative max_total_bytes %d", this.MaxTotalBytes)
+	}
+	var err error
+	policy.MaxAge, err = parseConfigDuration("max_age", this.MaxAge)
+	return policy, err
+}
+
+func (this *SyslogConfig) resolve(
+	setup *loggerSetup,
+) error {
+	switch this.Network {
+	case "", "unixgram", "unix", "udp", "tcp":
+	default:
+		return fmt.Errorf("unknown network %q", this.Network)
+	}
+	if this.Network != "" && this.Address == "" {
+		return errors.New("missing address")
+	}
+
+	switch strings.ToLower(this.Format) {
+	case "", "rfc3164":
+		setup.format = SyslogRFC3164
+	case "rfc5424":
+		setup.format = SyslogRFC5424
+	default:
+		return fmt.Errorf("unknown format %q", this.Format)
+	}
+
+	setup.facility = FacilityUser
+	if this.Facility != "" {
+		facility, ok := syslogFacilityNames[strings.ToLower(this.Facility)]
+		if !ok {
+			return fmt.Errorf("unknown facility %q", this.Facility)
+		}
+		setup.facility = facility
+	}
+	return nil
+}
+
+func (this *OverrideConfig) resolve(
+	loggerSeverities SeverityMask,
+) (subsystemOverride, error) {
+	override := subsystemOverride{
+		subsystem:  Subsystem(this.Subsystem),
+		severities: loggerSeverities,
+		verbosity:  Verbosity(this.Verbosity),
+	}
+	if this.Subsystem == "" {
+		return override, errors.New("missing subsystem")
+	}
+	if this.Verbosity < 0 {
+		return override, fmt.Errorf("negative verbosity %d", this.Verbosity)
+	}
+	if strings.TrimSpace(this.Severities) != "" {
+		severities, err := ParseSeverityMask(this.Severities)
+		if err != nil {
+			return override, err
+		}
+		override.severities = severities
+	}
+	return override, nil
+}
+
+func (this *AsyncConfig) resolve(
+	setup *loggerSetup,
+) error {
+	if this.QueueSize <= 0 {
+		return fmt.Errorf("invalid queue_size %d", this.QueueSize)
+	}
+	switch strings.ToLower(this.Policy) {
+	case "", "block":
+		setup.policy = AsyncBlock
+	case "drop-newest":
+		setup.policy = AsyncDropNewest
+	case "drop-by-severity":
+		setup.policy = AsyncDropBySeverity
+	default:
+		return fmt.Errorf("unknown policy %q", this.Policy)
+	}
+	return nil
+}
+
+func parseConfigDuration(
+	name string,
+	value string,
+) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", name, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("negative %s %q", name, value)
+	}
+	return duration, nil
+}
+
+func parseConfigWeekday(
+	value string,
+) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), value) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// Create the logger
+//
+// Returns:
+//     the logger
+//     rotators which must be added to the rotator of the global log
+//     an error of opening of the files
+func (this *loggerSetup) newLogger(
+	timesrc TimeSource,
+) (Logger, []LogRotator, error) {
+	config := this.config
+	var logger Logger
+	var rotators []LogRotator
+
+	/* -- the file holder */
+	var holder FileHolder
+	var err error
+	switch {
+	case config.Type == "console":
+		output := os.Stdout
+		if config.Output == "stderr" {
+			output = os.Stderr
+		}
+		holder = NewSimpleFileHandle(output, false)
+	case this.rotatable:
+		var rotatable RotatableFileHolder
+		rotatable, err = NewRotatableFileWithOptionsE(
+			config.Path, config.Sync, this.rotation)
+		if err == nil {
+			holder = rotatable
+			rotators = append(rotators, rotatable)
+		}
+	case config.Pattern != "":
+		var pattern RotatableFileHolder
+		pattern, err = NewPatternFileWithOptionsE(
+			timesrc, config.Pattern, config.Sync, this.pattern)
+		if err == nil {
+			holder = pattern
+			rotators = append(rotators, pattern)
+		}
+	case config.Path != "":
+		holder, err = NewSimpleFileE(config.Path, config.Sync)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	/* -- the logger */
+	switch config.Type {
+	case "apache":
+		if config.LogFormat == "" {
+			logger = NewApacheLogger(holder)
+		} else {
+			/* -- the format has been already validated */
+			logger, _ = NewApacheFormatLogger(holder, config.LogFormat)
+		}
+	case "syslog":
+		network, address := "", ""
+		if config.Syslog != nil {
+			network, address = config.Syslog.Network, config.Syslog.Address
+		}
+		logger = NewSyslogLogger(timesrc, network, address, this.format, this.facility)
+	default:
+		var formatter LineFormatter
+		if this.json {
+			formatter = newDefaultLineFormatterJSON()
+		} else {
+			formatter = NewLineFormatterDefault(config.Type == "console")
+		}
+		logger = NewFileLogger(timesrc, holder, formatter)
+	}
+	if holder != nil {
+		/* -- the logger keeps its own reference */
+		holder.Unref()
+	}
+
+	if config.Async != nil {
+		logger = NewAsyncLogger(logger, config.Async.QueueSize, this.policy)
+	}
+	return logger, rotators, nil
+}
+
+// Initialize the global log from a configuration file
+//
+// The configuration is validated and all logging files are opened before
+// the global log is initialized. If anything fails, the global log
+// isn't touched.
+//
+// Parameters:
+//     path: path of the JSON configuration file (see Config)
+// Returns:
+//     an error naming the offending logger
+func InitFromConfig(
+	path string,
+) error {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return InitWithConfig(config)
+}
+
+// Initialize the global log from a configuration
+//
+// Parameters:
+//     config: the configuration
+// Returns:
+//     an error naming the offending logger
+func InitWithConfig(
+	config *Config,
+) error {
+	return InitWithConfigAndTimeSource(config, NewTimeSourceLocal())
+}
+
+// Initialize the global log from a configuration with specified time source
+func InitWithConfigAndTimeSource(
+	config *Config,
+	timesrc TimeSource,
+) error {
+	setups, err := config.resolve()
+	if err != nil {
+		return err
+	}
+	snapshot, err := config.clone()
+	if err != nil {
+		return err
+	}
+
+	type builtLogger struct {
+		setup    *loggerSetup
+		logger   Logger
+		rotators []LogRotator
+	}
+	built := make([]builtLogger, 0, len(setups))
+	for _, setup := range setups {
+		logger, rotators, err := setup.newLogger(timesrc)
+		if err != nil {
+			for _, item := range built {
+				item.logger.Destroy()
+			}
+			return fmt.Errorf("logger %q: %s", setup.config.Name, err)
+		}
+		built = append(built, builtLogger{setup, logger, rotators})
+	}
+
+	InitWithTimeSource(config.System, timesrc)
+	setGlobalConfig(snapshot)
+	for _, item := range built {
+		for _, rotator := range item.rotators {
+			AddLogRotator(rotator)
+		}
+	}
+	Reconfigure(func(loggers LoggerSet) {
+		for _, item := range built {
+			item.setup.addLogger(loggers, item.logger)
+		}
+	})
+	return nil
+}
+
+// Add the created logger into the set
+func (this *loggerSetup) addLogger(
+	loggers LoggerSet,
+	logger Logger,
+) {
+	loggers.AddLogger(
+		this.config.Name,
+		Subsystem(this.config.Subsystem),
+		this.severities,
+		Verbosity(this.config.Verbosity),
+		logger)
+	this.setOverrides(loggers)
+}
+
+// Set the subsystem overrides of the logger
+func (this *loggerSetup) setOverrides(
+	loggers LoggerSet,
+) {
+	for _, override := range this.overrides {
+		loggers.SetLoggerSubsystemVerbosity(
+			this.config.Name,
+			override.subsystem,
+			override.severities,
+			override.verbosity)
+	}
+}
@@ -0,0 +1,115 @@
+package goolog2_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestParseConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{`{"loggers": []}`, "missing system identifier"},
+		{`{"system": "test", "loggers": [{"type": "file", "path": "x.log"}]}`, "logger #1: missing name"},
+		{`{"system": "test", "loggers": [{"name": "a", "type": "console"}, {"name": "a", "type": "console"}]}`, `logger "a": duplicated name`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "files"}]}`, `logger "main": unknown type "files"`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "file"}]}`, `logger "main": missing path`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "file", "path": "x.log", "severities": "error,fatal"}]}`, `logger "main": unknown severity "fatal"`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "file", "path": "x.log", "pattern": "%Y.log"}]}`, `logger "main": pattern isn't supported by file loggers`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "rotatable", "path": "x.log"}]}`, `logger "main": missing rotation`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "rotatable", "path": "x.log", "rotation": {"max_size": 100, "check_interval": "5 minutes"}}]}`, `logger "main": rotation: invalid check_interval`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "rotatable", "path": "x.log", "rotation": {"schedule": "monthly"}}]}`, `logger "main": rotation: unknown schedule "monthly"`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "syslog", "syslog": {"facility": "local9"}}]}`, `logger "main": syslog: unknown facility "local9"`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "console", "async": {"queue_size": 0}}]}`, `logger "main": async: invalid queue_size 0`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "console", "verbose": 3}]}`, `unknown field "verbose"`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "apache", "path": "x.log", "log_format": "%h %Z"}]}`, `logger "main": log_format: directive %Z: unsupported directive`},
+		{`{"system": "test", "loggers": [{"name": "main", "type": "file", "path": "x.log", "log_format": "%h"}]}`, `logger "main": log_format isn't supported by file loggers`},
+	}
+
+	for i, test := range tests {
+		_, err := ParseConfig([]byte(test.config))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestInitFromConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	config := `{
+  "system": "testlog",
+  "loggers": [
+    {
+      "name": "main",
+      "type": "rotatable",
+      "severities": "std",
+      "verbosity": 2,
+      "path": "` + filepath.Join(dir, "main.log") + `",
+      "rotation": {"max_size": 1048576, "check_interval": "1m", "compression": "gzip"},
+      "retention": {"max_generations": 3}
+    },
+    {
+      "name": "json",
+      "type": "file",
+      "subsystem": "db",
+      "severities": "error, warning",
+      "verbosity": 1,
+      "path": "` + filepath.Join(dir, "db.log") + `",
+      "formatter": "json",
+      "async": {"queue_size": 16, "policy": "drop-by-severity"}
+    }
+  ]
+}`
+	configPath := filepath.Join(dir, "log.json")
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitFromConfig(configPath); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	loggers := ListLoggers()
+	if len(loggers) != 2 ||
+		loggers[0] != (LoggerInfo{"json", "db", MaskError | MaskWarning, 1}) ||
+		loggers[1] != (LoggerInfo{"main", "", MaskStd, 2}) {
+		t.Errorf("unexpected loggers: %v", loggers)
+	}
+	Error1s("db", "database failed")
+	Debug3("invisible")
+	Destroy()
+
+	content, _ := ioutil.ReadFile(filepath.Join(dir, "main.log"))
+	if !strings.HasSuffix(string(content), "database failed\n") {
+		t.Errorf("unexpected content of the main log: %q", content)
+	}
+	content, _ = ioutil.ReadFile(filepath.Join(dir, "db.log"))
+	if !strings.Contains(string(content), `"message":"database failed"`) {
+		t.Errorf("unexpected content of the json log: %q", content)
+	}
+}
+
+func TestInitFromConfigOpenError(t *testing.T) {
+	config := &Config{
+		System: "testlog",
+		Loggers: []LoggerConfig{
+			{Name: "console", Type: "console", Verbosity: 1},
+			{Name: "broken", Type: "file", Path: "missing-directory/file.log", Verbosity: 1},
+		},
+	}
+	err := InitWithConfig(config)
+	if err == nil || !strings.HasPrefix(err.Error(), `logger "broken": open missing-directory/file.log`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package goolog2
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Handler of errors of the logging framework
+//
+// The loggers report failures of opening, writing and rotating of
+// the logging files through the handler. The handler is invoked
+// synchronously from the logging goroutine (or from the rotator).
+// Warning: the handler must not log through the failing logger.
+type ErrorHandler interface {
+	// Handle an error
+	HandleLogError(
+		err error)
+}
+
+// Adapter of an ordinary function to the ErrorHandler interface
+type ErrorHandlerFunc func(err error)
+
+func (this ErrorHandlerFunc) HandleLogError(
+	err error,
+) {
+	this(err)
+}
+
+// Object which can report its errors
+//
+// Loggers and file holders implementing this interface get the error
+// handler of the dispatcher they're added to.
+type ErrorReporter interface {
+	// Set the error handler
+	//
+	// Parameters:
+	//     handler: the handler. Nil disables reporting.
+	SetErrorHandler(
+		handler ErrorHandler)
+}
+
+type writerErrorHandler struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// Create new error handler printing the errors into the standard error output
+func NewErrorHandlerStderr() ErrorHandler {
+	return NewErrorHandlerWriter(os.Stderr)
+}
+
+// Create new error handler printing the errors into a writer
+//
+// Parameters:
+//     writer: the writer
+// Returns:
+//     the handler
+func NewErrorHandlerWriter(
+	writer io.Writer,
+) ErrorHandler {
+	return &writerErrorHandler{writer: writer}
+}
+
+func (this *writerErrorHandler) HandleLogError(
+	err error,
+) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	fmt.Fprintf(this.writer, "goolog2: logging failed: %s\n", err)
+}
+
+type rateLimitedErrorHandler struct {
+	handler    ErrorHandler
+	interval   time.Duration
+	mutex      sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// Error reported after suppressed errors
+//
+// The error wraps the reported error without fmt.Errorf("%w"), which
+// requires Go 1.13.
+type suppressedErrors struct {
+	err        error
+	suppressed int
+}
+
+func (this *suppressedErrors) Error() string {
+	return fmt.Sprintf("%s (%d more errors suppressed)", this.err, this.suppressed)
+}
+
+// Get the reported error (see errors.Unwrap)
+func (this *suppressedErrors) Unwrap() error {
+	return this.err
+}
+
+// Create new error handler limiting rate of the reported errors
+//
+// At most one error per interval is passed to the wrapped handler.
+// The number of suppressed errors is attached to the next passed one.
+//
+// Parameters:
+//     handler: the wrapped handler
+//     interval: minimal interval between two passed errors
+// Returns:
+//     the handler
+func NewRateLimitedErrorHandler(
+	handler ErrorHandler,
+	interval time.Duration,
+) ErrorHandler {
+	return &rateLimitedErrorHandler{
+		handler:  handler,
+		interval: interval,
+	}
+}
+
+func (this *rateLimitedErrorHandler) HandleLogError(
+	err error,
+) {
+	this.mutex.Lock()
+	now := time.Now()
+	if !this.last.IsZero() && now.Sub(this.last) < this.interval {
+		this.suppressed++
+		this.mutex.Unlock()
+		return
+	}
+	this.last = now
+	suppressed := this.suppressed
+	this.suppressed = 0
+	this.mutex.Unlock()
+
+	if suppressed > 0 {
+		err = &suppressedErrors{err: err, suppressed: suppressed}
+	}
+	this.handler.HandleLogError(err)
+}
+
+// Error reporting shared by the file holders
+//
+// Errors which occur before a handler is set (e.g. in the constructor)
+// are kept and the first one is reported when the handler is set.
+type errorSink struct {
+	errorMutex sync.Mutex
+	handler    ErrorHandler
+	pending    error
+}
+
+func (this *errorSink) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	this.errorMutex.Lock()
+	this.handler = handler
+	pending := this.pending
+	if handler != nil {
+		this.pending = nil
+	}
+	this.errorMutex.Unlock()
+
+	if handler != nil && pending != nil {
+		handler.HandleLogError(pending)
+	}
+}
+
+func (this *errorSink) reportError(
+	err error,
+) {
+	if err == nil {
+		return
+	}
+	this.errorMutex.Lock()
+	handler := this.handler
+	if handler == nil && this.pending == nil {
+		this.pending = err
+	}
+	this.errorMutex.Unlock()
+
+	if handler != nil {
+		handler.HandleLogError(err)
+	}
+}
+
+// File writer remembering the first write error
+type errorTrackingWriter struct {
+	FileWriter
+	err error
+}
+
+func (this *errorTrackingWriter) reset(
+	writer FileWriter,
+) {
+	this.FileWriter = writer
+	this.err = nil
+}
+
+func (this *errorTrackingWriter) Write(
+	p []byte,
+) (int, error) {
+	n, err := this.FileWriter.Write(p)
+	/* -- The closed writer means the file couldn't be opened. The opening
+	   error has been already reported. */
+	if err != nil && err != os.ErrClosed && this.err == nil {
+		this.err = err
+	}
+	return n, err
+}
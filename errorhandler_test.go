@@ -0,0 +1,192 @@
+package goolog2_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+type collectingErrorHandler struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (this *collectingErrorHandler) HandleLogError(
+	err error,
+) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.errors = append(this.errors, err)
+}
+
+func (this *collectingErrorHandler) Errors() []error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]error(nil), this.errors...)
+}
+
+func TestErrorConstructors(t *testing.T) {
+	t.Parallel()
+
+	const missing = "missing-directory/file.log"
+	holder, err := NewSimpleFileE(missing, false)
+	if holder != nil || !os.IsNotExist(err) {
+		t.Errorf("the simple file shouldn't be opened: %v", err)
+	}
+	if holder, err := NewRotatableFileE(missing, false, 100, time.Minute); holder != nil || err == nil {
+		t.Errorf("the rotatable file shouldn't be opened")
+	}
+	if holder, err := NewPatternFileE(NewTimeSourceLocal(), "missing-directory/%Y.log", false); holder != nil || err == nil {
+		t.Errorf("the pattern file shouldn't be opened")
+	}
+}
+
+func TestErrorHandlerOpenError(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	f := NewSimpleFile("missing-directory/file.log", false)
+	dispatcher.AddLogger(
+		"file", "", MaskAll, 5,
+		NewFileLogger(NewTimeSourceLocal(), f, NewLineFormatterDefault(false)))
+	f.Unref()
+
+	/* -- the opening error is reported when the handler is set */
+	handler := &collectingErrorHandler{}
+	dispatcher.SetErrorHandler(handler)
+	errs := handler.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "missing-directory/file.log") {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	/* -- logging into the unopened file doesn't report anything more */
+	DispatcherLogMessage(dispatcher, "", Error, 1, "lost message")
+	if errs := handler.Errors(); len(errs) != 1 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestErrorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	/* -- the file opened for reading makes the writes fail */
+	file, err := os.Open("errorhandler_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	handler := &collectingErrorHandler{}
+	dispatcher.SetErrorHandler(handler)
+	f := NewSimpleFileHandle(file, false)
+	dispatcher.AddLogger(
+		"file", "", MaskAll, 5,
+		NewFileLogger(NewTimeSourceLocal(), f, NewLineFormatterDefault(false)))
+	f.Unref()
+
+	/* -- the rate of the errors is limited */
+	for i := 0; i < 3; i++ {
+		DispatcherLogMessage(dispatcher, "", Error, 1, "message")
+	}
+	if errs := handler.Errors(); len(errs) != 1 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestErrorHandlerRetentionError(t *testing.T) {
+	t.Parallel()
+
+	directory, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(directory)
+
+	/* -- a non-empty directory cannot be removed by the retention */
+	if err := os.MkdirAll(directory+"/rotatable.log.1/nested", 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := NewRotatableFileWithOptions(
+		directory+"/rotatable.log", false,
+		RotatableFileOptions{Retention: RetentionPolicy{MaxGenerations: 1}})
+	defer f.Unref()
+	handler := &collectingErrorHandler{}
+	f.(ErrorReporter).SetErrorHandler(handler)
+
+	f.Rotate(NewTimeSourceLocal())
+	errs := handler.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "rotatable.log.2") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestFileHolderPanickingWriter(t *testing.T) {
+	t.Parallel()
+
+	directory, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(directory)
+	holders := []FileHolder{
+		NewSimpleFile(directory+"/simple.log", false),
+		NewRotatableFile(directory+"/rotatable.log", false, 0, 0),
+		NewPatternFile(NewTimeSourceLocal(), directory+"/pattern-%Y.log", false),
+	}
+	for _, holder := range holders {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("the panic is not propagated")
+				}
+			}()
+			holder.AccessWriter(func(writer FileWriter) {
+				panic("formatter failure")
+			})
+		}()
+
+		/* -- the holder must stay usable */
+		done := make(chan struct{})
+		go func() {
+			holder.AccessWriter(func(writer FileWriter) {})
+			holder.Unref()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the holder is still locked")
+		}
+	}
+}
+
+func TestRateLimitedErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := &collectingErrorHandler{}
+	limited := NewRateLimitedErrorHandler(handler, 50*time.Millisecond)
+	limited.HandleLogError(errors.New("first"))
+	limited.HandleLogError(errors.New("second"))
+	limited.HandleLogError(errors.New("third"))
+	time.Sleep(60 * time.Millisecond)
+	fourth := errors.New("fourth")
+	limited.HandleLogError(fourth)
+
+	errs := handler.Errors()
+	if len(errs) != 2 || errs[0].Error() != "first" ||
+		errs[1].Error() != "fourth (2 more errors suppressed)" {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if wrapper, ok := errs[1].(interface{ Unwrap() error }); !ok || wrapper.Unwrap() != fourth {
+		t.Errorf("the reported error isn't wrapped: %v", errs[1])
+	}
+}
@@ -0,0 +1,17 @@
+package goolog2
+
+import (
+	"time"
+)
+
+// Change the reconnection delay of a syslog logger (see NewSyslogLogger)
+func SetSyslogReconnectDelay(
+	logger Logger,
+	delay time.Duration,
+) {
+	syslog := logger.(*syslogLogger)
+	syslog.mutex.Lock()
+	defer syslog.mutex.Unlock()
+	syslog.reconnectDelay = delay
+	syslog.retry = time.Time{}
+}
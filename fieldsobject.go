@@ -0,0 +1,100 @@
+package goolog2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// One structured field of a log message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Structured logging object
+//
+// This interface extends the line object by ordered key/value pairs.
+// The line is just the message without the fields, hence loggers which
+// know only line objects keep working.
+type FieldsObject interface {
+	LineObject
+
+	// Get the ordered key/value pairs
+	GetLogFields() []Field
+}
+
+// Key used for a value missing its key in a key/value list
+const badFieldKey = "!BADKEY"
+
+type fieldsMessageObject struct {
+	message string
+	fields  []Field
+}
+
+func (this *fieldsMessageObject) GetLogLine() string {
+	return this.message
+}
+
+func (this *fieldsMessageObject) GetLogFields() []Field {
+	return this.fields
+}
+
+// Convert alternating keys and values into fields
+//
+// A Field item in the list is taken as a whole. A value without a key
+// is stored with the key "!BADKEY".
+func keyValuesToFields(
+	keyvals []interface{},
+) []Field {
+	fields := make([]Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i++ {
+		switch key := keyvals[i].(type) {
+		case Field:
+			fields = append(fields, key)
+		case string:
+			if i+1 < len(keyvals) {
+				fields = append(fields, Field{key, keyvals[i+1]})
+				i++
+			} else {
+				fields = append(fields, Field{badFieldKey, key})
+			}
+		default:
+			fields = append(fields, Field{badFieldKey, key})
+		}
+	}
+	return fields
+}
+
+// Format the fields as a sequence of " key=value" items
+func formatFields(
+	fields []Field,
+) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	builder := &strings.Builder{}
+	for _, field := range fields {
+		builder.WriteByte(' ')
+		builder.WriteString(quoteFieldText(field.Key))
+		builder.WriteByte('=')
+		builder.WriteString(quoteFieldText(fmt.Sprint(field.Value)))
+	}
+	return builder.String()
+}
+
+// Quote the text if it would break the key=value syntax
+func quoteFieldText(
+	text string,
+) string {
+	if text == "" {
+		return `""`
+	}
+	for _, c := range text {
+		if c == '=' || c == '"' || unicode.IsSpace(c) || !unicode.IsPrint(c) {
+			return strconv.Quote(text)
+		}
+	}
+	return text
+}
@@ -0,0 +1,75 @@
+package goolog2_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+type plainFormatter struct {
+}
+
+func (this *plainFormatter) FormatLine(
+	writer FileWriter,
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	line string,
+) {
+	writer.Write([]byte(line + "\n"))
+}
+
+func TestFieldsObject(t *testing.T) {
+	logfile := "fields.log"
+	plainfile := "fields-plain.log"
+	os.Remove(logfile)
+	os.Remove(plainfile)
+	defer os.Remove(logfile)
+	defer os.Remove(plainfile)
+
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:27")
+	timesrc := NewMockTimeSource(now)
+
+	InitWithTimeSource("testlog", timesrc)
+	AddFileLogger("file", "", MaskAll, 5, logfile, false)
+	plain := NewSimpleFile(plainfile, false)
+	AddLogger("plain", "", MaskAll, 5, NewFileLogger(timesrc, plain, &plainFormatter{}))
+	plain.Unref()
+
+	Info2kv("request done", "user", "me", "ms", 25)
+	Error1kvs("db", "query failed", "err", errors.New("no connection"), "query", "")
+	LogFields("", Warning, 1, "fields", Field{"a", 1}, Field{"b=c", true})
+	Info1kv("bad keys", "key", "value", 42, Field{"field", 1.5}, "last")
+	Info1("no fields")
+
+	Destroy()
+
+	expected := "" +
+		"testlog 2018-08-25T14:02:27 [    INFO, 2] (): request done user=me ms=25\n" +
+		"testlog 2018-08-25T14:02:27 [   ERROR, 1] (db): query failed err=\"no connection\" query=\"\"\n" +
+		"testlog 2018-08-25T14:02:27 [ WARNING, 1] (): fields a=1 \"b=c\"=true\n" +
+		"testlog 2018-08-25T14:02:27 [    INFO, 1] (): bad keys key=value !BADKEY=42 field=1.5 !BADKEY=last\n" +
+		"testlog 2018-08-25T14:02:27 [    INFO, 1] (): no fields\n"
+	current, err := ioutil.ReadFile(logfile)
+	if err != nil || string(current) != expected {
+		t.Errorf("unexpected log file:\n%s", string(current))
+	}
+
+	/* -- formatters not knowing fields get them appended to the line */
+	expected = "" +
+		"request done user=me ms=25\n" +
+		"query failed err=\"no connection\" query=\"\"\n" +
+		"fields a=1 \"b=c\"=true\n" +
+		"bad keys key=value !BADKEY=42 field=1.5 !BADKEY=last\n" +
+		"no fields\n"
+	current, err = ioutil.ReadFile(plainfile)
+	if err != nil || string(current) != expected {
+		t.Errorf("unexpected plain log file:\n%s", string(current))
+	}
+}
@@ -60,3 +60,17 @@ type FileHolder interface {
 	//     if the reference counter reaches zero!
 	Unref()
 }
+
+// Holder of a file which can be reopened
+//
+// External tools (like logrotate) can rename or remove the logging file.
+// Then the holder keeps writing into the renamed file until it's reopened.
+type ReopenableFileHolder interface {
+	FileHolder
+
+	// Close the file and open it again by its path
+	Reopen() error
+
+	// Check whether the path doesn't point to the opened file anymore
+	PathChanged() bool
+}
@@ -1,11 +1,14 @@
 package goolog2
 
-import ()
+import (
+	"time"
+)
 
 type fileLogger struct {
-	timesrc   TimeSource
-	file      FileHolder
-	formatter LineFormatter
+	timesrc         TimeSource
+	file            FileHolder
+	formatter       LineFormatter
+	fieldsFormatter FieldsLineFormatter
 }
 
 // Create new file logger
@@ -21,10 +24,12 @@ func NewFileLogger(
 	file FileHolder,
 	formatter LineFormatter,
 ) Logger {
+	fieldsFormatter, _ := formatter.(FieldsLineFormatter)
 	return &fileLogger{
-		timesrc:   timesrc,
-		file:      file.Ref(),
-		formatter: formatter,
+		timesrc:         timesrc,
+		file:            file.Ref(),
+		formatter:       formatter,
+		fieldsFormatter: fieldsFormatter,
 	}
 }
 
@@ -32,12 +37,36 @@ func (this *fileLogger) Destroy() {
 	this.file.Unref()
 }
 
+func (this *fileLogger) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	if reporter, ok := this.file.(ErrorReporter); ok {
+		reporter.SetErrorHandler(handler)
+	}
+}
+
 func (this *fileLogger) LogObject(
 	system string,
 	subsystem Subsystem,
 	severity Severity,
 	verbosity Verbosity,
 	object interface{},
+) {
+	this.logObjectAt(
+		this.timesrc.Now(), system, subsystem, severity, verbosity, object)
+}
+
+func (this *fileLogger) loggerNow() time.Time {
+	return this.timesrc.Now()
+}
+
+func (this *fileLogger) logObjectAt(
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
 ) {
 	/* -- the logger supports only line objects */
 	line, ok := object.(LineObject)
@@ -45,15 +74,38 @@ func (this *fileLogger) LogObject(
 		return
 	}
 
+	/* -- structured fields. If the formatter doesn't know them, they
+	   are appended to the line. */
+	message := line.GetLogLine()
+	var fields []Field
+	if fieldsObject, ok := object.(FieldsObject); ok {
+		fields = fieldsObject.GetLogFields()
+		if this.fieldsFormatter == nil {
+			message += formatFields(fields)
+		}
+	}
+
 	/* -- write the message */
 	this.file.AccessWriter(func(writer FileWriter) {
-		this.formatter.FormatLine(
-			writer,
-			this.timesrc.Now(),
-			system,
-			subsystem,
-			severity,
-			verbosity,
-			line.GetLogLine())
+		if this.fieldsFormatter != nil {
+			this.fieldsFormatter.FormatLineFields(
+				writer,
+				now,
+				system,
+				subsystem,
+				severity,
+				verbosity,
+				message,
+				fields)
+		} else {
+			this.formatter.FormatLine(
+				writer,
+				now,
+				system,
+				subsystem,
+				severity,
+				verbosity,
+				message)
+		}
 	})
 }
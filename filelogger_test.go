@@ -10,49 +10,12 @@ import (
 	. "github.com/Staon/goolog2"
 )
 
-type mockTimeSource struct {
-	now time.Time
-}
-
-func (this *mockTimeSource) Now() time.Time {
-	return this.now
-}
-
-// Change the time and wait to rotators actions scheduled for this time or earlier.
-// Paramaters:
-//      newTime - string in the format "2006-01-02T15:04:05"
-// Returns:
-//      New mocked time
-func (this *mockTimeSource) SetTime(newTime string) time.Time {
-	now, _ := time.Parse("2006-01-02T15:04:05", newTime)
-	if this.now != now {
-		this.now = now
-		AfterChangeMockedTime(true)
-	}
-	return this.now
-}
-
-// Shift the time and wait to rotators actions scheduled for this time or earlier.
-// Paramaters:
-//      duration
-// Returns:
-//      New mocked time
-func (this *mockTimeSource) ShiftTime(duration time.Duration) time.Time {
-	if duration > 0 {
-		this.now = this.now.Add(duration)
-		AfterChangeMockedTime(true)
-	}
-	return this.now
-}
-
 func TestFileLogger(t *testing.T) {
 	logfile := "log.log"
 	os.Remove(logfile)
 
 	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:27")
-	timesrc := &mockTimeSource{
-		now: now,
-	}
+	timesrc := NewMockTimeSource(now)
 
 	InitWithTimeSource("testlog", timesrc)
 	AddFileLogger("file", "", MaskAll, 5, logfile, false)
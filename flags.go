@@ -0,0 +1,59 @@
+package goolog2
+
+import (
+	"flag"
+)
+
+// Name of the logger added by LogFlags.Apply
+const LogFlagsLoggerName = "flags"
+
+// Standard command line flags of the logging
+type LogFlags struct {
+	// Value of the -log-verbosity flag
+	Verbosity Verbosity
+	// Value of the -log-severities flag
+	Severities SeverityMask
+	// Value of the -log-file flag
+	File string
+}
+
+// Register standard logging flags
+//
+// The function registers flags -log-verbosity (default 1),
+// -log-severities (default "std") and -log-file (default is the standard
+// error output). Invoke the Apply method after the flags are parsed.
+//
+// Parameters:
+//     flags: the flag set. If it's nil, flag.CommandLine is used.
+// Returns:
+//     the values of the flags
+func RegisterLogFlags(
+	flags *flag.FlagSet,
+) *LogFlags {
+	if flags == nil {
+		flags = flag.CommandLine
+	}
+	values := &LogFlags{
+		Verbosity:  1,
+		Severities: MaskStd,
+	}
+	flags.Var(&values.Verbosity, "log-verbosity", "maximal verbosity of logged messages")
+	flags.Var(&values.Severities, "log-severities",
+		"logged severities: all, std, none or a comma separated list of critical, error, warning, info and debug")
+	flags.StringVar(&values.File, "log-file", "", "path of the logging file (the standard error output if it's empty)")
+	return values
+}
+
+// Add a logger configured by the flags into the global log
+//
+// The logger is named LogFlagsLoggerName. An existing logger
+// of the name is replaced.
+func (this *LogFlags) Apply() {
+	if this.File == "" {
+		AddConsoleLoggerStderr(
+			LogFlagsLoggerName, "", this.Severities, this.Verbosity)
+	} else {
+		AddFileLogger(
+			LogFlagsLoggerName, "", this.Severities, this.Verbosity, this.File, false)
+	}
+}
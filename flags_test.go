@@ -0,0 +1,53 @@
+package goolog2_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestLogFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	values := RegisterLogFlags(flags)
+	err = flags.Parse([]string{
+		"-log-verbosity", "3",
+		"-log-severities", "error,info",
+		"-log-file", filepath.Join(dir, "flags.log"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Init("testlog")
+	defer Destroy()
+	values.Apply()
+	loggers := ListLoggers()
+	if len(loggers) != 1 ||
+		loggers[0] != (LoggerInfo{LogFlagsLoggerName, "", MaskError | MaskInfo, 3}) {
+		t.Errorf("unexpected loggers: %v", loggers)
+	}
+	Info3("info")
+	Warning1("warning")
+	content, _ := ioutil.ReadFile(filepath.Join(dir, "flags.log"))
+	if !strings.HasSuffix(string(content), "info\n") || strings.Contains(string(content), "warning") {
+		t.Errorf("unexpected content of the log: %q", content)
+	}
+
+	/* -- invalid values */
+	flags = flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	RegisterLogFlags(flags)
+	if flags.Parse([]string{"-log-severities", "fatal"}) == nil {
+		t.Errorf("invalid severities should fail")
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 var globalLog LogDispatcher
 var timeSource TimeSource
-var globalRotator logsRotatorStarter
+var globalRotator *logsRotatorStarter
 
 // Initialize the global log
 //
@@ -24,27 +24,40 @@ func InitWithTimeSource(
 	system string,
 	timesrc TimeSource,
 ) {
+	if globalRotator != nil {
+		globalRotator.Destroy()
+	}
 	if globalLog != nil {
 		globalLog.Destroy()
 	}
 	timeSource = timesrc
 	globalLog = NewLogDispatcher(system)
 	globalRotator = newRotators(timeSource)
+	setGlobalConfig(nil)
 }
 
 // If mocked time source is used (see InitWithTimeSource), this method must be called after every time shift.
+// MockTimeSource invokes it automatically.
 //
 // Parameters:
 //     waitToRotators - true => Wait to finish corresponding rotators actions (scheduled for this time or earlier).
 func AfterChangeMockedTime(waitToRotators bool) {
-	globalRotator.OnMockedTimeChanged(waitToRotators)
+	if globalRotator != nil {
+		globalRotator.OnMockedTimeChanged(waitToRotators)
+	}
 }
 
 // Destroy the global log
+//
+// The goroutine running the rotators is stopped and joined, then
+// the loggers are destroyed.
 func Destroy() {
+	globalRotator.Destroy()
+	globalRotator = nil
 	globalLog.Destroy()
 	globalLog = nil
 	timeSource = nil
+	setGlobalConfig(nil)
 }
 
 // Add a logger
@@ -66,6 +79,124 @@ func AddLogger(
 	globalLog.AddLogger(name, subsystem, severities, verbosity, logger)
 }
 
+// Remove a logger
+//
+// The logger is destroyed after all running logging calls finish.
+//
+// Parameters:
+//     name: ID of the logger
+// Returns:
+//     false if there is no logger of the name
+func RemoveLogger(
+	name string,
+) bool {
+	return globalLog.RemoveLogger(name)
+}
+
+// Change verbosity of a logger
+//
+// Parameters:
+//     name: ID of the logger
+//     verbosity: new logging verbosity
+// Returns:
+//     false if there is no logger of the name
+func SetLoggerVerbosity(
+	name string,
+	verbosity Verbosity,
+) bool {
+	return globalLog.SetLoggerVerbosity(name, verbosity)
+}
+
+// Change severities of a logger
+//
+// Parameters:
+//     name: ID of the logger
+//     severities: new mask of logging severities
+// Returns:
+//     false if there is no logger of the name
+func SetLoggerSeverities(
+	name string,
+	severities SeverityMask,
+) bool {
+	return globalLog.SetLoggerSeverities(name, severities)
+}
+
+// Change subsystem of a logger
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: new logging subsystem. Can be empty.
+// Returns:
+//     false if there is no logger of the name
+func SetLoggerSubsystem(
+	name string,
+	subsystem Subsystem,
+) bool {
+	return globalLog.SetLoggerSubsystem(name, subsystem)
+}
+
+// Override severities and verbosity of a logger for a subsystem
+//
+// See LogDispatcher.SetLoggerSubsystemVerbosity.
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: the subsystem
+//     severities: mask of severities logged for the subsystem
+//     verbosity: maximal verbosity logged for the subsystem
+// Returns:
+//     false if there is no logger of the name
+func SetLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+) bool {
+	return globalLog.SetLoggerSubsystemVerbosity(
+		name, subsystem, severities, verbosity)
+}
+
+// Remove an override of severities and verbosity of a logger
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: the subsystem
+// Returns:
+//     false if there is no logger of the name or no such override
+func ClearLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+) bool {
+	return globalLog.ClearLoggerSubsystemVerbosity(name, subsystem)
+}
+
+// Get a snapshot of registered loggers
+//
+// Returns:
+//     descriptions of the loggers sorted by their names
+func ListLoggers() []LoggerInfo {
+	return globalLog.ListLoggers()
+}
+
+// Change several global loggers atomically
+//
+// See LogDispatcher.Reconfigure.
+func Reconfigure(
+	functor func(loggers LoggerSet),
+) {
+	globalLog.Reconfigure(functor)
+}
+
+// Set handler of errors of the global loggers
+//
+// Parameters:
+//     handler: the handler. Nil disables reporting of the errors.
+func SetErrorHandler(
+	handler ErrorHandler,
+) {
+	globalLog.SetErrorHandler(handler)
+}
+
 // Add a rotator. The rotator is usually connected to one particular logger.
 // See interface LogRotator. Methods NeedRotate + Rotate are runed in separate goroutine.
 // The start time of boths method is determined by the method GetNextCheckTime.
@@ -78,6 +209,20 @@ func AddLogRotator(
 	globalRotator.Add(rotator)
 }
 
+// Remove a rotator
+//
+// The rotatable and pattern file holders are removed automatically
+// when they're destroyed (the logger using them is removed). When the
+// function returns, the rotator isn't running and it won't be run anymore.
+//
+// Parameters:
+//     rotator: the rotator
+func RemoveLogRotator(
+	rotator LogRotator,
+) {
+	globalRotator.Remove(rotator)
+}
+
 // Add a simple file logger
 //
 // Parameters:
@@ -129,6 +274,93 @@ func AddRotatableFileLogger(
 	AddLogger(name, subsystem, severities, verbosity, logger)
 }
 
+// Add a rotatable file logger with options (It rotate file.log => file.log.1 => file.log.2 => ...)
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     file: path to the logging file
+//     sync: flush all message immediately
+//     options: options of the rotation
+func AddRotatableFileLoggerWithOptions(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	file string,
+	sync bool,
+	options RotatableFileOptions,
+) {
+	f := NewRotatableFileWithOptions(file, sync, options)
+	defer f.Unref()
+	logger := NewFileLogger(timeSource, f, NewLineFormatterDefault(false))
+	AddLogRotator(f)
+	AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
+// Add a JSON file logger
+//
+// The logger writes one JSON object per line (see NewLineFormatterJSON)
+// with millisecond timestamps.
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     file: path to the logging file
+//     sync: flush all message immediately
+func AddJSONFileLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	file string,
+	sync bool,
+) {
+	f := NewSimpleFile(file, sync)
+	defer f.Unref()
+	logger := NewFileLogger(timeSource, f, newDefaultLineFormatterJSON())
+	AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
+// Add a rotatable JSON file logger (It rotate file.log => file.log.1 => file.log.2 => ...)
+//
+// The logger writes one JSON object per line (see NewLineFormatterJSON)
+// with millisecond timestamps.
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     file: path to the logging file
+//     sync: flush all message immediately
+//     maxSize:  Make log rotation if log size is bigger than maxSize.
+//     checkInterval: time interval to check the log size; usually minutes or tens of minutes
+func AddRotatableJSONFileLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	file string,
+	sync bool,
+	maxSize int64,
+	checkInterval time.Duration,
+) {
+	f := NewRotatableFile(file, sync, maxSize, checkInterval)
+	defer f.Unref()
+	logger := NewFileLogger(timeSource, f, newDefaultLineFormatterJSON())
+	AddLogRotator(f)
+	AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
+func newDefaultLineFormatterJSON() LineFormatter {
+	return NewLineFormatterJSON(LineFormatterJSONOptions{TimePrecision: 3})
+}
+
 // Add a pattern file logger
 //
 // Parameters:
@@ -153,6 +385,32 @@ func AddPatternFileLogger(
 	AddLogger(name, subsystem, severities, verbosity, logger)
 }
 
+// Add a pattern file logger with options
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     pattern: pattern of names the log files
+//     sync: flush all message immediately
+//     options: options of the pattern file
+func AddPatternFileLoggerWithOptions(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	pattern string,
+	sync bool,
+	options PatternFileOptions,
+) {
+	f := NewPatternFileWithOptions(timeSource, pattern, sync, options)
+	defer f.Unref()
+	logger := NewFileLogger(timeSource, f, NewLineFormatterDefault(false))
+	AddLogRotator(f)
+	AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
 // Add a console logger
 //
 // Parameters:
@@ -216,6 +474,37 @@ func AddApacheLogger(
 	AddLogger(name, subsystem, severities, verbosity, logger)
 }
 
+// Add a Apache logger with a custom format
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     file: path to the logging file
+//     sync: flush all message immediately
+//     format: the Apache logging format (see NewApacheFormatLogger)
+// Returns:
+//     an error if the format is invalid
+func AddApacheFormatLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	file string,
+	sync bool,
+	format string,
+) error {
+	f := NewSimpleFile(file, sync)
+	defer f.Unref()
+	logger, err := NewApacheFormatLogger(f, format)
+	if err != nil {
+		return err
+	}
+	AddLogger(name, subsystem, severities, verbosity, logger)
+	return nil
+}
+
 // Add a rotatable Apache logger (It rotate access.log => access.log.1 => access.log.2 => ...)
 //
 // Parameters:
@@ -238,7 +527,6 @@ func AddRotatableApacheLogger(
 	checkInterval time.Duration,
 ) {
 	f := NewRotatableFile(file, sync, maxSize, checkInterval)
-	globalRotator.Add(f)
 	defer f.Unref()
 	logger := NewApacheLogger(f)
 	AddLogRotator(f)
@@ -269,6 +557,131 @@ func AddPatternApacheLogger(
 	AddLogger(name, subsystem, severities, verbosity, logger)
 }
 
+// Add a syslog logger
+//
+// Parameters:
+//     name: ID of the logger
+//     subsystem: logging subsystem. Can be empty.
+//     severities: mask of logging severities
+//     verbosity: logging verbosity
+//     network: "unixgram", "unix", "udp" or "tcp". Empty string means
+//         the local syslog socket.
+//     address: address of the syslog daemon. Can be empty for the local
+//         syslog.
+//     format: format of the syslog messages
+//     facility: syslog facility
+func AddSyslogLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	network string,
+	address string,
+	format SyslogFormat,
+	facility SyslogFacility,
+) {
+	logger := NewSyslogLogger(timeSource, network, address, format, facility)
+	AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
+// Dispatcher forwarding to the global log
+//
+// The proxy always refers the current global log, even if it's
+// initialized again.
+type globalDispatcher struct {
+}
+
+func (this *globalDispatcher) Destroy() {
+	/* -- the global log is destroyed only by the global Destroy() */
+}
+
+func (this *globalDispatcher) LogObject(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	globalLog.LogObject(subsystem, severity, verbosity, object)
+}
+
+func (this *globalDispatcher) Enabled(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) bool {
+	return globalLog.Enabled(subsystem, severity, verbosity)
+}
+
+func (this *globalDispatcher) AddLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	logger Logger,
+) {
+	globalLog.AddLogger(name, subsystem, severities, verbosity, logger)
+}
+
+func (this *globalDispatcher) RemoveLogger(
+	name string,
+) bool {
+	return globalLog.RemoveLogger(name)
+}
+
+func (this *globalDispatcher) SetLoggerVerbosity(
+	name string,
+	verbosity Verbosity,
+) bool {
+	return globalLog.SetLoggerVerbosity(name, verbosity)
+}
+
+func (this *globalDispatcher) SetLoggerSeverities(
+	name string,
+	severities SeverityMask,
+) bool {
+	return globalLog.SetLoggerSeverities(name, severities)
+}
+
+func (this *globalDispatcher) SetLoggerSubsystem(
+	name string,
+	subsystem Subsystem,
+) bool {
+	return globalLog.SetLoggerSubsystem(name, subsystem)
+}
+
+func (this *globalDispatcher) SetLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+) bool {
+	return globalLog.SetLoggerSubsystemVerbosity(
+		name, subsystem, severities, verbosity)
+}
+
+func (this *globalDispatcher) ClearLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+) bool {
+	return globalLog.ClearLoggerSubsystemVerbosity(name, subsystem)
+}
+
+func (this *globalDispatcher) ListLoggers() []LoggerInfo {
+	return globalLog.ListLoggers()
+}
+
+func (this *globalDispatcher) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	globalLog.SetErrorHandler(handler)
+}
+
+func (this *globalDispatcher) Reconfigure(
+	functor func(loggers LoggerSet),
+) {
+	globalLog.Reconfigure(functor)
+}
+
 // Log a logging object into the global log
 //
 // Parameters:
@@ -285,6 +698,20 @@ func LogObject(
 	DispatcherLogObject(globalLog, subsystem, severity, verbosity, object)
 }
 
+// Check whether a message would be logged into the global log
+//
+// Parameters:
+//     subsystem: logging subsystem
+//     severity: logging severity
+//     verbosity: logging verbosity
+func Enabled(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) bool {
+	return globalLog.Enabled(subsystem, severity, verbosity)
+}
+
 // Log a text message
 //
 // Parameters:
@@ -320,11 +747,69 @@ func LogMessagef(
 		globalLog, subsystem, severity, verbosity, format, args...)
 }
 
+// Log a text message produced only if it's logged
+//
+// Parameters:
+//     subsystem: logging subsystem
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     producer: a function producing the message
+func LogMessagel(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, severity, verbosity, producer)
+}
+
+// Log a message with structured fields
+//
+// Parameters:
+//     subsystem: logging subsystem
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     message: the message
+//     fields: structured fields of the message
+func LogFields(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	fields ...Field,
+) {
+	DispatcherLogFields(
+		globalLog, subsystem, severity, verbosity, message, fields...)
+}
+
+// Log a message with structured fields
+//
+// Parameters:
+//     subsystem: logging subsystem
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     message: the message
+//     keyvals: alternating keys and values of the fields
+func LogMessagekv(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(
+		globalLog, subsystem, severity, verbosity, message, keyvals...)
+}
+
 // There are a set of convenient logging functions. Their names follow
 // the pattern:
-//     <severity><verbosity>[f][s]
+//     <severity><verbosity>[f|kv|l][s]
 //
 //     f .... the message is formatted
+//     kv ... the message is followed by alternating keys and values
+//            of structured fields
+//     l .... the message is produced by a function invoked only
+//            if the message is logged
 //     s .... a subsystem is specified
 
 /* -- critical errors */
@@ -356,6 +841,34 @@ func Critical1fs(
 	DispatcherLogMessagef(globalLog, subsystem, Critical, 1, format, args...)
 }
 
+func Critical1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Critical, 1, message, keyvals...)
+}
+
+func Critical1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Critical, 1, message, keyvals...)
+}
+
+func Critical1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Critical, 1, producer)
+}
+
+func Critical1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Critical, 1, producer)
+}
+
 /* -- errors */
 func Error1(
 	message string,
@@ -385,6 +898,34 @@ func Error1fs(
 	DispatcherLogMessagef(globalLog, subsystem, Error, 1, format, args...)
 }
 
+func Error1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Error, 1, message, keyvals...)
+}
+
+func Error1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Error, 1, message, keyvals...)
+}
+
+func Error1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Error, 1, producer)
+}
+
+func Error1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Error, 1, producer)
+}
+
 func Error2(
 	message string,
 ) {
@@ -413,6 +954,34 @@ func Error2fs(
 	DispatcherLogMessagef(globalLog, subsystem, Error, 2, format, args...)
 }
 
+func Error2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Error, 2, message, keyvals...)
+}
+
+func Error2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Error, 2, message, keyvals...)
+}
+
+func Error2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Error, 2, producer)
+}
+
+func Error2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Error, 2, producer)
+}
+
 func Error3(
 	message string,
 ) {
@@ -441,6 +1010,34 @@ func Error3fs(
 	DispatcherLogMessagef(globalLog, subsystem, Error, 3, format, args...)
 }
 
+func Error3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Error, 3, message, keyvals...)
+}
+
+func Error3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Error, 3, message, keyvals...)
+}
+
+func Error3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Error, 3, producer)
+}
+
+func Error3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Error, 3, producer)
+}
+
 func Error4(
 	message string,
 ) {
@@ -469,6 +1066,34 @@ func Error4fs(
 	DispatcherLogMessagef(globalLog, subsystem, Error, 4, format, args...)
 }
 
+func Error4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Error, 4, message, keyvals...)
+}
+
+func Error4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Error, 4, message, keyvals...)
+}
+
+func Error4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Error, 4, producer)
+}
+
+func Error4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Error, 4, producer)
+}
+
 /* -- warnings */
 
 func Warning1(
@@ -499,6 +1124,34 @@ func Warning1fs(
 	DispatcherLogMessagef(globalLog, subsystem, Warning, 1, format, args...)
 }
 
+func Warning1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Warning, 1, message, keyvals...)
+}
+
+func Warning1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Warning, 1, message, keyvals...)
+}
+
+func Warning1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Warning, 1, producer)
+}
+
+func Warning1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Warning, 1, producer)
+}
+
 func Warning2(
 	message string,
 ) {
@@ -527,6 +1180,34 @@ func Warning2fs(
 	DispatcherLogMessagef(globalLog, subsystem, Warning, 2, format, args...)
 }
 
+func Warning2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Warning, 2, message, keyvals...)
+}
+
+func Warning2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Warning, 2, message, keyvals...)
+}
+
+func Warning2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Warning, 2, producer)
+}
+
+func Warning2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Warning, 2, producer)
+}
+
 func Warning3(
 	message string,
 ) {
@@ -555,6 +1236,34 @@ func Warning3fs(
 	DispatcherLogMessagef(globalLog, subsystem, Warning, 3, format, args...)
 }
 
+func Warning3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Warning, 3, message, keyvals...)
+}
+
+func Warning3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Warning, 3, message, keyvals...)
+}
+
+func Warning3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Warning, 3, producer)
+}
+
+func Warning3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Warning, 3, producer)
+}
+
 func Warning4(
 	message string,
 ) {
@@ -583,6 +1292,34 @@ func Warning4fs(
 	DispatcherLogMessagef(globalLog, subsystem, Warning, 4, format, args...)
 }
 
+func Warning4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Warning, 4, message, keyvals...)
+}
+
+func Warning4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Warning, 4, message, keyvals...)
+}
+
+func Warning4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Warning, 4, producer)
+}
+
+func Warning4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Warning, 4, producer)
+}
+
 /* -- info messages */
 func Info1(
 	message string,
@@ -612,6 +1349,34 @@ func Info1fs(
 	DispatcherLogMessagef(globalLog, subsystem, Info, 1, format, args...)
 }
 
+func Info1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Info, 1, message, keyvals...)
+}
+
+func Info1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Info, 1, message, keyvals...)
+}
+
+func Info1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Info, 1, producer)
+}
+
+func Info1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Info, 1, producer)
+}
+
 func Info2(
 	message string,
 ) {
@@ -640,6 +1405,34 @@ func Info2fs(
 	DispatcherLogMessagef(globalLog, subsystem, Info, 2, format, args...)
 }
 
+func Info2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Info, 2, message, keyvals...)
+}
+
+func Info2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Info, 2, message, keyvals...)
+}
+
+func Info2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Info, 2, producer)
+}
+
+func Info2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Info, 2, producer)
+}
+
 func Info3(
 	message string,
 ) {
@@ -668,6 +1461,34 @@ func Info3fs(
 	DispatcherLogMessagef(globalLog, subsystem, Info, 3, format, args...)
 }
 
+func Info3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Info, 3, message, keyvals...)
+}
+
+func Info3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Info, 3, message, keyvals...)
+}
+
+func Info3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Info, 3, producer)
+}
+
+func Info3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Info, 3, producer)
+}
+
 func Info4(
 	message string,
 ) {
@@ -696,6 +1517,34 @@ func Info4fs(
 	DispatcherLogMessagef(globalLog, subsystem, Info, 4, format, args...)
 }
 
+func Info4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Info, 4, message, keyvals...)
+}
+
+func Info4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Info, 4, message, keyvals...)
+}
+
+func Info4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Info, 4, producer)
+}
+
+func Info4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Info, 4, producer)
+}
+
 func Info5(
 	message string,
 ) {
@@ -724,6 +1573,34 @@ func Info5fs(
 	DispatcherLogMessagef(globalLog, subsystem, Info, 5, format, args...)
 }
 
+func Info5kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Info, 5, message, keyvals...)
+}
+
+func Info5kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Info, 5, message, keyvals...)
+}
+
+func Info5l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Info, 5, producer)
+}
+
+func Info5ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Info, 5, producer)
+}
+
 /* -- debug messages */
 
 func Debug3(
@@ -754,6 +1631,34 @@ func Debug3fs(
 	DispatcherLogMessagef(globalLog, subsystem, Debug, 3, format, args...)
 }
 
+func Debug3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Debug, 3, message, keyvals...)
+}
+
+func Debug3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Debug, 3, message, keyvals...)
+}
+
+func Debug3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Debug, 3, producer)
+}
+
+func Debug3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Debug, 3, producer)
+}
+
 func Debug4(
 	message string,
 ) {
@@ -782,6 +1687,34 @@ func Debug4fs(
 	DispatcherLogMessagef(globalLog, subsystem, Debug, 4, format, args...)
 }
 
+func Debug4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Debug, 4, message, keyvals...)
+}
+
+func Debug4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Debug, 4, message, keyvals...)
+}
+
+func Debug4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Debug, 4, producer)
+}
+
+func Debug4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Debug, 4, producer)
+}
+
 func Debug5(
 	message string,
 ) {
@@ -809,3 +1742,31 @@ func Debug5fs(
 ) {
 	DispatcherLogMessagef(globalLog, subsystem, Debug, 5, format, args...)
 }
+
+func Debug5kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, "", Debug, 5, message, keyvals...)
+}
+
+func Debug5kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(globalLog, subsystem, Debug, 5, message, keyvals...)
+}
+
+func Debug5l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, "", Debug, 5, producer)
+}
+
+func Debug5ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(globalLog, subsystem, Debug, 5, producer)
+}
@@ -0,0 +1,107 @@
+package goolog2test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/Staon/goolog2"
+)
+
+// Logging system of the global log initialized by Install
+const System = "test"
+
+// Name of the memory logger added by Install
+const LoggerName = "memory"
+
+/* -- the memory logger installed into the global log */
+var installed *MemoryLogger
+var installedMutex sync.Mutex
+
+// Install a memory logger into the global log
+//
+// The global log is initialized and the memory logger accepting all
+// messages is added into it. The returned function destroys the global
+// log, defer it to clean up when the test finishes:
+//
+//     defer goolog2test.Install(t)()
+//
+// The installed logger is available by Installed. The tests using
+// the global log cannot run in parallel.
+//
+// Parameters:
+//     t: the test
+// Returns:
+//     the cleanup function
+func Install(
+	t testing.TB,
+) func() {
+	t.Helper()
+	logger := NewMemoryLogger()
+	goolog2.Init(System)
+	goolog2.AddLogger(
+		LoggerName, "", goolog2.MaskAll, goolog2.Verbosity(math.MaxUint32), logger)
+
+	installedMutex.Lock()
+	installed = logger
+	installedMutex.Unlock()
+
+	return func() {
+		installedMutex.Lock()
+		if installed == logger {
+			installed = nil
+		}
+		installedMutex.Unlock()
+		goolog2.Destroy()
+	}
+}
+
+// Get the memory logger installed by Install
+//
+// The test fails immediately if no logger is installed.
+func Installed(
+	t testing.TB,
+) *MemoryLogger {
+	t.Helper()
+	installedMutex.Lock()
+	logger := installed
+	installedMutex.Unlock()
+	if logger == nil {
+		t.Fatalf("goolog2test: no memory logger is installed")
+	}
+	return logger
+}
+
+// Report a test failure if a message hasn't been logged into the global log
+//
+// The function works with the logger installed by Install.
+//
+// Parameters:
+//     t: the test
+//     severity: severity of the message
+//     text: a text which the message line must contain
+func ExpectLogged(
+	t testing.TB,
+	severity goolog2.Severity,
+	text string,
+) {
+	t.Helper()
+	Installed(t).ExpectLogged(t, severity, text)
+}
+
+// Report a test failure if a message has been logged into the global log
+//
+// The function works with the logger installed by Install.
+//
+// Parameters:
+//     t: the test
+//     severity: severity of the message
+//     text: a text which the message line mustn't contain
+func ExpectNotLogged(
+	t testing.TB,
+	severity goolog2.Severity,
+	text string,
+) {
+	t.Helper()
+	Installed(t).ExpectNotLogged(t, severity, text)
+}
@@ -0,0 +1,189 @@
+// Package goolog2test provides helpers for testing of code which logs
+// through goolog2.
+//
+// The MemoryLogger records all logged messages in the memory. Install
+// puts it into the global log for the duration of a test:
+//
+//     func TestConnection(t *testing.T) {
+//         defer goolog2test.Install(t)()
+//         connect()
+//         goolog2test.ExpectLogged(t, goolog2.Error, "connection failed")
+//     }
+package goolog2test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Staon/goolog2"
+)
+
+// One recorded message
+type Record struct {
+	System    string
+	Subsystem goolog2.Subsystem
+	Severity  goolog2.Severity
+	Verbosity goolog2.Verbosity
+	// The message line (empty if the object isn't a line object)
+	Line string
+	// Structured fields of the message (nil if there are no fields)
+	Fields []goolog2.Field
+	// The logged object
+	Object interface{}
+}
+
+// Logger recording the messages in the memory
+//
+// The logger is safe to be used by concurrent goroutines.
+type MemoryLogger struct {
+	mutex     sync.Mutex
+	records   []Record
+	destroyed bool
+}
+
+// Create new memory logger
+func NewMemoryLogger() *MemoryLogger {
+	return &MemoryLogger{}
+}
+
+func (this *MemoryLogger) Destroy() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.destroyed = true
+}
+
+func (this *MemoryLogger) LogObject(
+	system string,
+	subsystem goolog2.Subsystem,
+	severity goolog2.Severity,
+	verbosity goolog2.Verbosity,
+	object interface{},
+) {
+	record := Record{
+		System:    system,
+		Subsystem: subsystem,
+		Severity:  severity,
+		Verbosity: verbosity,
+		Object:    object,
+	}
+	if line, ok := object.(goolog2.LineObject); ok {
+		record.Line = line.GetLogLine()
+	}
+	if fields, ok := object.(goolog2.FieldsObject); ok {
+		record.Fields = fields.GetLogFields()
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.records = append(this.records, record)
+}
+
+// Check whether the logger has been destroyed
+func (this *MemoryLogger) Destroyed() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.destroyed
+}
+
+// Get a copy of the recorded messages
+func (this *MemoryLogger) Records() []Record {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]Record(nil), this.records...)
+}
+
+// Forget all recorded messages
+func (this *MemoryLogger) Reset() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.records = nil
+}
+
+// Find recorded messages
+//
+// Parameters:
+//     severity: severity of the messages
+//     text: a text which the message line must contain. Empty text
+//         matches any message.
+// Returns:
+//     the matching messages
+func (this *MemoryLogger) Find(
+	severity goolog2.Severity,
+	text string,
+) []Record {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	var found []Record
+	for _, record := range this.records {
+		if record.Severity == severity && strings.Contains(record.Line, text) {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+// Check whether a message has been recorded
+//
+// See Find for description of the parameters.
+func (this *MemoryLogger) Contains(
+	severity goolog2.Severity,
+	text string,
+) bool {
+	return len(this.Find(severity, text)) > 0
+}
+
+// Report a test failure if a message hasn't been recorded
+//
+// See Find for description of the parameters.
+func (this *MemoryLogger) ExpectLogged(
+	t testing.TB,
+	severity goolog2.Severity,
+	text string,
+) {
+	t.Helper()
+	if !this.Contains(severity, text) {
+		t.Errorf(
+			"expected %s message containing %q, logged:\n%s",
+			severity, text, this.dump())
+	}
+}
+
+// Report a test failure if a message has been recorded
+//
+// See Find for description of the parameters.
+func (this *MemoryLogger) ExpectNotLogged(
+	t testing.TB,
+	severity goolog2.Severity,
+	text string,
+) {
+	t.Helper()
+	if this.Contains(severity, text) {
+		t.Errorf(
+			"unexpected %s message containing %q, logged:\n%s",
+			severity, text, this.dump())
+	}
+}
+
+// Describe the recorded messages for a failure report
+func (this *MemoryLogger) dump() string {
+	records := this.Records()
+	if len(records) == 0 {
+		return "    (nothing)"
+	}
+	var builder strings.Builder
+	for i, record := range records {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString("    [")
+		builder.WriteString(record.Severity.String())
+		builder.WriteString(", ")
+		builder.WriteString(record.Verbosity.String())
+		builder.WriteString("] (")
+		builder.WriteString(string(record.Subsystem))
+		builder.WriteString("): ")
+		builder.WriteString(record.Line)
+	}
+	return builder.String()
+}
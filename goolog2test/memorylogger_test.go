@@ -0,0 +1,87 @@
+package goolog2test_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Staon/goolog2"
+	. "github.com/Staon/goolog2/goolog2test"
+)
+
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (this *failureRecorder) Helper() {
+	/* -- nothing to do */
+}
+
+func (this *failureRecorder) Errorf(
+	format string,
+	args ...interface{},
+) {
+	this.failures = append(this.failures, fmt.Sprintf(format, args...))
+}
+
+func TestMemoryLogger(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := goolog2.NewLogDispatcher("testlog")
+	logger := NewMemoryLogger()
+	dispatcher.AddLogger("memory", "", goolog2.MaskStd, 2, logger)
+	log := goolog2.NewLog(dispatcher, "db")
+	log.Error1f("connection failed: %s", "timeout")
+	log.Info2kv("pool", "size", 4)
+	log.Debug3("invisible")
+
+	records := logger.Records()
+	if len(records) != 2 {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	if records[0].System != "testlog" || records[0].Subsystem != "db" ||
+		records[0].Severity != goolog2.Error || records[0].Verbosity != 1 ||
+		records[0].Line != "connection failed: timeout" {
+		t.Errorf("unexpected record: %+v", records[0])
+	}
+	if len(records[1].Fields) != 1 || records[1].Fields[0] != (goolog2.Field{Key: "size", Value: 4}) {
+		t.Errorf("unexpected fields: %v", records[1].Fields)
+	}
+
+	recorder := &failureRecorder{}
+	logger.ExpectLogged(recorder, goolog2.Error, "connection failed")
+	logger.ExpectNotLogged(recorder, goolog2.Debug, "")
+	if len(recorder.failures) != 0 {
+		t.Errorf("unexpected failures: %v", recorder.failures)
+	}
+	logger.ExpectLogged(recorder, goolog2.Warning, "connection failed")
+	logger.ExpectNotLogged(recorder, goolog2.Info, "pool")
+	if len(recorder.failures) != 2 {
+		t.Errorf("unexpected failures: %v", recorder.failures)
+	}
+
+	logger.Reset()
+	if len(logger.Records()) != 0 {
+		t.Errorf("the records aren't forgotten")
+	}
+	dispatcher.Destroy()
+	if !logger.Destroyed() {
+		t.Errorf("the logger isn't destroyed")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	var logger *MemoryLogger
+	t.Run("installed", func(t *testing.T) {
+		defer Install(t)()
+		logger = Installed(t)
+		goolog2.Error2s("db", "connection failed")
+		goolog2.Debug5("details")
+		ExpectLogged(t, goolog2.Error, "connection failed")
+		ExpectLogged(t, goolog2.Debug, "details")
+		ExpectNotLogged(t, goolog2.Critical, "")
+	})
+	if !logger.Destroyed() {
+		t.Errorf("the global log isn't destroyed after the test")
+	}
+}
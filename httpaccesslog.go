@@ -0,0 +1,384 @@
+package goolog2
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Options of the HTTP access logging
+type AccessLogOptions struct {
+	// Subsystem of the access records. Can be empty.
+	Subsystem Subsystem
+	// Severity of the access records. Zero means Info.
+	Severity Severity
+	// Verbosity of the access records. Zero means the verbosity 1.
+	Verbosity Verbosity
+	// Time source of the request times. If it's nil, the time source
+	// of the global log is used for the global dispatcher and the local
+	// time for other dispatchers.
+	TimeSource TimeSource
+	// Addresses (192.168.1.10) or networks (10.0.0.0/8) of trusted proxies.
+	// If a request comes from a trusted proxy, the remote host is taken
+	// from the X-Forwarded-For header.
+	TrustedProxies []string
+}
+
+type accessLogMiddleware struct {
+	dispatcher LogDispatcher
+	options    AccessLogOptions
+	proxies    []*net.IPNet
+}
+
+// Access record of one HTTP request
+//
+// The values are copied from the request when the request finishes,
+// hence the record can be logged asynchronously.
+type httpAccessObject struct {
+	remoteHost  string
+	user        string
+	requestTime time.Time
+	method      string
+	resource    string
+	protocol    string
+	status      int
+	length      uint64
+	referer     string
+	agent       string
+	duration    time.Duration
+	request     http.Header
+	response    http.Header
+}
+
+// Create HTTP middleware logging access records
+//
+// The middleware logs one ApacheRequestObject per request (see
+// NewApacheLogger and NewApacheFormatLogger).
+// The request time is the time when the request comes. The user is taken
+// from the basic authentication.
+//
+// Parameters:
+//     dispatcher: the dispatcher receiving the records
+//     options: options of the logging
+// Returns:
+//     the middleware wrapping a handler
+//     an error if a trusted proxy cannot be parsed
+func NewAccessLogMiddleware(
+	dispatcher LogDispatcher,
+	options AccessLogOptions,
+) (func(http.Handler) http.Handler, error) {
+	if options.Severity == 0 {
+		options.Severity = Info
+	}
+	if options.Verbosity == 0 {
+		options.Verbosity = 1
+	}
+	middleware := &accessLogMiddleware{
+		dispatcher: dispatcher,
+		options:    options,
+	}
+	for _, proxy := range options.TrustedProxies {
+		cidr := proxy
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+		}
+		middleware.proxies = append(middleware.proxies, network)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			middleware.serve(next, writer, request)
+		})
+	}, nil
+}
+
+func (this *accessLogMiddleware) now() time.Time {
+	timesrc := this.options.TimeSource
+	if timesrc == nil {
+		if _, global := this.dispatcher.(*globalDispatcher); global && timeSource != nil {
+			timesrc = timeSource
+		} else {
+			return time.Now()
+		}
+	}
+	return timesrc.Now()
+}
+
+func (this *accessLogMiddleware) serve(
+	next http.Handler,
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+	if !this.dispatcher.Enabled(
+		this.options.Subsystem, this.options.Severity, this.options.Verbosity) {
+		next.ServeHTTP(writer, request)
+		return
+	}
+
+	requestTime := this.now()
+	recorder, state := newResponseRecorder(writer)
+	finished := false
+	defer func() {
+		/* -- a panic is logged as the internal error. The panic isn't
+		      recovered to keep its original stack. */
+		if !finished && state.status == 0 {
+			state.status = http.StatusInternalServerError
+		}
+		this.log(request, writer.Header(), state, requestTime)
+	}()
+	next.ServeHTTP(recorder, request)
+	finished = true
+}
+
+func (this *accessLogMiddleware) log(
+	request *http.Request,
+	response http.Header,
+	state *responseState,
+	requestTime time.Time,
+) {
+	object := &httpAccessObject{
+		remoteHost:  this.remoteHost(request),
+		duration:    this.now().Sub(requestTime),
+		request:     cloneHeader(request.Header),
+		response:    cloneHeader(response),
+		requestTime: requestTime,
+		method:      request.Method,
+		resource:    request.RequestURI,
+		protocol:    request.Proto,
+		status:      state.status,
+		length:      state.length,
+		referer:     request.Referer(),
+		agent:       request.UserAgent(),
+	}
+	if user, _, ok := request.BasicAuth(); ok {
+		object.user = user
+	}
+	if object.resource == "" {
+		object.resource = request.URL.RequestURI()
+	}
+	if object.status == 0 {
+		if state.hijacked {
+			object.status = http.StatusSwitchingProtocols
+		} else {
+			object.status = http.StatusOK
+		}
+	}
+	DispatcherLogObject(
+		this.dispatcher,
+		this.options.Subsystem,
+		this.options.Severity,
+		this.options.Verbosity,
+		object)
+}
+
+// Copy the headers (http.Header.Clone requires Go 1.13)
+func cloneHeader(
+	header http.Header,
+) http.Header {
+	clone := make(http.Header, len(header))
+	for name, values := range header {
+		clone[name] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// Get the remote host of a request
+//
+// The X-Forwarded-For header is walked from the right (the closest proxy)
+// while the addresses are trusted. The first untrusted address is
+// the client.
+func (this *accessLogMiddleware) remoteHost(
+	request *http.Request,
+) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	if !this.trusted(host) {
+		return host
+	}
+	forwarded := strings.Split(strings.Join(request.Header["X-Forwarded-For"], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwarded[i])
+		if address == "" {
+			continue
+		}
+		host = address
+		if !this.trusted(address) {
+			break
+		}
+	}
+	return host
+}
+
+func (this *accessLogMiddleware) trusted(
+	host string,
+) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range this.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *httpAccessObject) GetValues() (
+	remoteHost string,
+	identity string,
+	user string,
+	requestTime time.Time,
+	method string,
+	resource string,
+	protocol string,
+	status int,
+	length uint64,
+	referer string,
+	agent string,
+) {
+	return this.remoteHost, "", this.user, this.requestTime, this.method,
+		this.resource, this.protocol, this.status, this.length,
+		this.referer, this.agent
+}
+
+func (this *httpAccessObject) GetDuration() time.Duration {
+	return this.duration
+}
+
+func (this *httpAccessObject) GetRequestHeader(
+	name string,
+) string {
+	return this.request.Get(name)
+}
+
+func (this *httpAccessObject) GetResponseHeader(
+	name string,
+) string {
+	return this.response.Get(name)
+}
+
+// State of a response captured by the recorder
+type responseState struct {
+	status   int
+	length   uint64
+	hijacked bool
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	state *responseState
+}
+
+type flushingRecorder struct {
+	*responseRecorder
+}
+
+type hijackingRecorder struct {
+	*responseRecorder
+}
+
+type flushingHijackingRecorder struct {
+	*responseRecorder
+}
+
+// Wrap a response writer capturing the status and the length
+//
+// The wrapper implements http.Flusher and http.Hijacker only if
+// the original writer does, hence the handlers see the same behavior.
+//
+// Returns:
+//     the wrapper
+//     the captured state
+func newResponseRecorder(
+	writer http.ResponseWriter,
+) (http.ResponseWriter, *responseState) {
+	recorder := &responseRecorder{
+		ResponseWriter: writer,
+		state:          &responseState{},
+	}
+	_, flusher := writer.(http.Flusher)
+	_, hijacker := writer.(http.Hijacker)
+	var wrapped http.ResponseWriter
+	switch {
+	case flusher && hijacker:
+		wrapped = flushingHijackingRecorder{recorder}
+	case flusher:
+		wrapped = flushingRecorder{recorder}
+	case hijacker:
+		wrapped = hijackingRecorder{recorder}
+	default:
+		wrapped = recorder
+	}
+	return wrapped, recorder.state
+}
+
+func (this *responseRecorder) WriteHeader(
+	status int,
+) {
+	/* -- informational responses are followed by the final one */
+	if this.state.status == 0 && status >= 200 {
+		this.state.status = status
+	}
+	this.ResponseWriter.WriteHeader(status)
+}
+
+func (this *responseRecorder) Write(
+	data []byte,
+) (int, error) {
+	if this.state.status == 0 {
+		this.state.status = http.StatusOK
+	}
+	written, err := this.ResponseWriter.Write(data)
+	this.state.length += uint64(written)
+	return written, err
+}
+
+// Get the original writer (see http.ResponseController)
+func (this *responseRecorder) Unwrap() http.ResponseWriter {
+	return this.ResponseWriter
+}
+
+func (this *responseRecorder) flush() {
+	if this.state.status == 0 {
+		this.state.status = http.StatusOK
+	}
+	this.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (this *responseRecorder) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buffer, err := this.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		this.state.hijacked = true
+	}
+	return conn, buffer, err
+}
+
+func (this flushingRecorder) Flush() {
+	this.flush()
+}
+
+func (this hijackingRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return this.hijack()
+}
+
+func (this flushingHijackingRecorder) Flush() {
+	this.flush()
+}
+
+func (this flushingHijackingRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return this.hijack()
+}
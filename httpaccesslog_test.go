@@ -0,0 +1,166 @@
+package goolog2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+	"github.com/Staon/goolog2/goolog2test"
+)
+
+func newAccessLogTest(
+	t *testing.T,
+	options AccessLogOptions,
+	handler http.HandlerFunc,
+) (http.Handler, *goolog2test.MemoryLogger, LogDispatcher) {
+	dispatcher := NewLogDispatcher("testlog")
+	memory := goolog2test.NewMemoryLogger()
+	dispatcher.AddLogger("memory", "", MaskAll, 5, memory)
+	middleware, err := NewAccessLogMiddleware(dispatcher, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return middleware(handler), memory, dispatcher
+}
+
+func lastAccessRecord(
+	t *testing.T,
+	memory *goolog2test.MemoryLogger,
+) ApacheObject {
+	records := memory.Records()
+	if len(records) == 0 {
+		t.Fatalf("no access record")
+	}
+	object, ok := records[len(records)-1].Object.(ApacheObject)
+	if !ok {
+		t.Fatalf("the record isn't an apache object")
+	}
+	return object
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	t.Parallel()
+
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	handler, memory, dispatcher := newAccessLogTest(
+		t,
+		AccessLogOptions{
+			Subsystem:      "http",
+			TimeSource:     NewMockTimeSource(now),
+			TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+		},
+		func(writer http.ResponseWriter, request *http.Request) {
+			if _, ok := writer.(http.Flusher); !ok {
+				t.Errorf("the writer isn't a flusher")
+			}
+			if _, ok := writer.(http.Hijacker); ok {
+				t.Errorf("the writer is a hijacker")
+			}
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("not found"))
+		})
+	defer dispatcher.Destroy()
+
+	request := httptest.NewRequest("GET", "/items?id=1", nil)
+	request.RemoteAddr = "192.168.1.1:4000"
+	request.Header.Set("X-Forwarded-For", "203.0.113.7, 10.1.2.3")
+	request.Header.Set("Referer", "http://example.com/")
+	request.Header.Set("User-Agent", "test/1.0")
+	request.SetBasicAuth("joe", "secret")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	records := memory.Records()
+	if len(records) != 1 || records[0].Subsystem != "http" ||
+		records[0].Severity != Info || records[0].Verbosity != 1 {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	remoteHost, _, user, requestTime, method, resource, protocol,
+		status, length, referer, agent := lastAccessRecord(t, memory).GetValues()
+	if remoteHost != "203.0.113.7" || user != "joe" || !requestTime.Equal(now) ||
+		method != "GET" || resource != "/items?id=1" || protocol != "HTTP/1.1" ||
+		status != 404 || length != 9 || referer != "http://example.com/" || agent != "test/1.0" {
+		t.Errorf("unexpected values: %s %s %s %s %s %s %d %d %s %s",
+			remoteHost, user, requestTime, method, resource, protocol, status, length, referer, agent)
+	}
+
+	/* -- untrusted peer */
+	request.RemoteAddr = "198.51.100.1:4000"
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	if remoteHost, _, _, _, _, _, _, _, _, _, _ := lastAccessRecord(t, memory).GetValues(); remoteHost != "198.51.100.1" {
+		t.Errorf("the header of an untrusted peer is used: %s", remoteHost)
+	}
+}
+
+func TestAccessLogMiddlewareHijack(t *testing.T) {
+	t.Parallel()
+
+	handler, memory, dispatcher := newAccessLogTest(
+		t,
+		AccessLogOptions{},
+		func(writer http.ResponseWriter, request *http.Request) {
+			conn, buffer, err := writer.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijacking failed: %s", err)
+				return
+			}
+			buffer.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+			buffer.Flush()
+			conn.Close()
+		})
+	defer dispatcher.Destroy()
+
+	/* -- the record is logged when the middleware returns */
+	served := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			defer close(served)
+			handler.ServeHTTP(writer, request)
+		}))
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	response.Body.Close()
+	<-served
+
+	if _, _, _, _, _, _, _, status, _, _, _ := lastAccessRecord(t, memory).GetValues(); status != 101 {
+		t.Errorf("unexpected status: %d", status)
+	}
+}
+
+func TestAccessLogMiddlewarePanic(t *testing.T) {
+	t.Parallel()
+
+	handler, memory, dispatcher := newAccessLogTest(
+		t,
+		AccessLogOptions{},
+		func(writer http.ResponseWriter, request *http.Request) {
+			panic("handler failure")
+		})
+	defer dispatcher.Destroy()
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "handler failure" {
+				t.Errorf("unexpected panic: %v", recovered)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+	if _, _, _, _, _, _, _, status, _, _, _ := lastAccessRecord(t, memory).GetValues(); status != 500 {
+		t.Errorf("unexpected status: %d", status)
+	}
+}
+
+func TestAccessLogMiddlewareErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAccessLogMiddleware(
+		NewLogDispatcher("testlog"), AccessLogOptions{TrustedProxies: []string{"proxy"}})
+	if err == nil || err.Error() != `invalid trusted proxy "proxy"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
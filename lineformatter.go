@@ -27,3 +27,33 @@ type LineFormatter interface {
 		verbosity Verbosity,
 		line string)
 }
+
+// Line formatter aware of structured fields
+//
+// If a line formatter implements this interface, the file logger passes
+// fields of the FieldsObject objects to it. Otherwise the fields are
+// appended to the line as key=value pairs.
+type FieldsLineFormatter interface {
+	LineFormatter
+
+	// Format a line with fields
+	//
+	// Parameters:
+	//     writer: line writer
+	//     now: current time
+	//     system: logging system
+	//     subsystem: logging subsystem (can be empty)
+	//     severity: severity of the logging message
+	//     verbosity: verbosity of the logging message
+	//     line: the logging message
+	//     fields: structured fields of the message (can be empty)
+	FormatLineFields(
+		writer FileWriter,
+		now time.Time,
+		system string,
+		subsystem Subsystem,
+		severity Severity,
+		verbosity Verbosity,
+		line string,
+		fields []Field)
+}
@@ -29,6 +29,20 @@ func (this *lineFormatterDefault) FormatLine(
 	severity Severity,
 	verbosity Verbosity,
 	line string,
+) {
+	this.FormatLineFields(
+		writer, now, system, subsystem, severity, verbosity, line, nil)
+}
+
+func (this *lineFormatterDefault) FormatLineFields(
+	writer FileWriter,
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	line string,
+	fields []Field,
 ) {
 	/* -- determine message color */
 	var color Color
@@ -46,21 +60,23 @@ func (this *lineFormatterDefault) FormatLine(
 	if this.short {
 		fmt.Fprintf(
 			writer,
-			"[%8s, %d] (%s): %s\n",
+			"[%8s, %d] (%s): %s%s\n",
 			severity.Code(),
 			verbosity,
 			subsystem,
-			line)
+			line,
+			formatFields(fields))
 	} else {
 		fmt.Fprintf(
 			writer,
-			"%s %s [%8s, %d] (%s): %s\n",
+			"%s %s [%8s, %d] (%s): %s%s\n",
 			system,
 			now.Format("2006-01-02T15:04:05"),
 			severity.Code(),
 			verbosity,
 			subsystem,
-			line)
+			line,
+			formatFields(fields))
 	}
 
 	/* -- reset the color back */
@@ -0,0 +1,203 @@
+package goolog2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Options of the JSON line formatter
+type LineFormatterJSONOptions struct {
+	// Number of fractional digits of the seconds in the timestamp (0 - 9)
+	TimePrecision int
+}
+
+type lineFormatterJSON struct {
+	timeLayout string
+}
+
+// Create new JSON line formatter
+//
+// The formatter writes one JSON object per line:
+//     {"system":"my_process","time":"2018-08-25T14:02:00.000Z",
+//      "severity":"ERROR","verbosity":1,"subsystem":"db",
+//      "message":"connection failed","fields":{"host":"db1"}}
+// The time is formatted according to RFC 3339. The fields are present
+// only if the logged object carries some.
+//
+// Parameters:
+//     options: options of the formatter
+func NewLineFormatterJSON(
+	options LineFormatterJSONOptions,
+) LineFormatter {
+	precision := options.TimePrecision
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > 9 {
+		precision = 9
+	}
+	layout := "2006-01-02T15:04:05"
+	if precision > 0 {
+		layout += "." + strings.Repeat("0", precision)
+	}
+	layout += "Z07:00"
+
+	return &lineFormatterJSON{
+		timeLayout: layout,
+	}
+}
+
+func (this *lineFormatterJSON) FormatLine(
+	writer FileWriter,
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	line string,
+) {
+	this.FormatLineFields(
+		writer, now, system, subsystem, severity, verbosity, line, nil)
+}
+
+func (this *lineFormatterJSON) FormatLineFields(
+	writer FileWriter,
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	line string,
+	fields []Field,
+) {
+	buffer := make([]byte, 0, 128+len(line))
+	buffer = append(buffer, `{"system":`...)
+	buffer = appendJSONString(buffer, system)
+	buffer = append(buffer, `,"time":`...)
+	buffer = appendJSONString(buffer, now.Format(this.timeLayout))
+	buffer = append(buffer, `,"severity":`...)
+	buffer = appendJSONString(buffer, severity.Code())
+	buffer = append(buffer, `,"verbosity":`...)
+	buffer = strconv.AppendUint(buffer, uint64(verbosity), 10)
+	buffer = append(buffer, `,"subsystem":`...)
+	buffer = appendJSONString(buffer, string(subsystem))
+	buffer = append(buffer, `,"message":`...)
+	buffer = appendJSONString(buffer, line)
+	if len(fields) > 0 {
+		buffer = append(buffer, `,"fields":{`...)
+		for i, field := range fields {
+			if i > 0 {
+				buffer = append(buffer, ',')
+			}
+			buffer = appendJSONString(buffer, field.Key)
+			buffer = append(buffer, ':')
+			buffer = appendJSONValue(buffer, field.Value)
+		}
+		buffer = append(buffer, '}')
+	}
+	buffer = append(buffer, "}\n"...)
+
+	/* -- one write keeps the line in one piece */
+	writer.Write(buffer)
+}
+
+// Append a value of a field
+//
+// Numbers and booleans are kept, times are formatted as RFC 3339 and
+// JSON marshalers produce their own form. Errors and stringers are
+// converted to strings (nil pointers as "<nil>"). Other values are
+// marshaled by the encoding/json package.
+func appendJSONValue(
+	buffer []byte,
+	value interface{},
+) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buffer, "null"...)
+	case string:
+		return appendJSONString(buffer, v)
+	case bool:
+		return strconv.AppendBool(buffer, v)
+	case int:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(buffer, v, 10)
+	case uint:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(buffer, v, 10)
+	case time.Time:
+		buffer = append(buffer, '"')
+		buffer = v.AppendFormat(buffer, time.RFC3339Nano)
+		return append(buffer, '"')
+	case json.Marshaler:
+		/* -- the own JSON form takes precedence over the text one */
+		break
+	case error, interface{ String() string }:
+		/* -- fmt handles nil receivers and panicking methods */
+		return appendJSONString(buffer, fmt.Sprint(v))
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		/* -- for example NaN - keep at least a text representation */
+		return appendJSONString(buffer, fmt.Sprint(value))
+	}
+	return append(buffer, data...)
+}
+
+// Append a string escaped according to the JSON rules
+//
+// Control characters are escaped, invalid UTF-8 sequences are replaced
+// by the replacement character U+FFFD.
+func appendJSONString(
+	buffer []byte,
+	text string,
+) []byte {
+	const hex = "0123456789abcdef"
+
+	buffer = append(buffer, '"')
+	for i := 0; i < len(text); {
+		c := text[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buffer = append(buffer, '\\', c)
+			case c == '\n':
+				buffer = append(buffer, '\\', 'n')
+			case c == '\r':
+				buffer = append(buffer, '\\', 'r')
+			case c == '\t':
+				buffer = append(buffer, '\\', 't')
+			case c < 0x20 || c == 0x7f:
+				buffer = append(buffer, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				buffer = append(buffer, c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buffer = append(buffer, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			/* -- valid JSON, however, they break JavaScript parsers */
+			buffer = append(buffer, `\u202`...)
+			buffer = append(buffer, hex[r&0xf])
+		default:
+			buffer = append(buffer, text[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buffer, '"')
+}
@@ -0,0 +1,139 @@
+package goolog2_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+type bufferWriter struct {
+	bytes.Buffer
+	colored bool
+}
+
+func (this *bufferWriter) Close() error {
+	return nil
+}
+
+func (this *bufferWriter) Stat() os.FileInfo {
+	return nil
+}
+
+func (this *bufferWriter) Sync() {
+}
+
+func (this *bufferWriter) ChangeColor(
+	color Color,
+) {
+	this.colored = true
+}
+
+func (this *bufferWriter) ResetColor() {
+}
+
+type pointerError struct {
+	message string
+}
+
+func (this *pointerError) Error() string {
+	return this.message
+}
+
+type jsonStringer struct {
+	value int
+}
+
+func (this *jsonStringer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"value": this.value})
+}
+
+func (this *jsonStringer) String() string {
+	return "stringer"
+}
+
+func TestLineFormatterJSON(t *testing.T) {
+	now, _ := time.Parse(
+		"2006-01-02T15:04:05.000000000 -0700", "2018-08-25T14:02:27.123456789 +0200")
+
+	tests := []struct {
+		precision int
+		subsystem Subsystem
+		line      string
+		fields    []Field
+		expected  string
+	}{
+		{
+			precision: 0,
+			line:      "simple",
+			expected:  `{"system":"testlog","time":"2018-08-25T14:02:27+02:00","severity":"ERROR","verbosity":2,"subsystem":"","message":"simple"}`,
+		},
+		{
+			precision: 3,
+			subsystem: "db",
+			line:      "quote \" backslash \\ newline \n tab \t bell \a",
+			expected:  `{"system":"testlog","time":"2018-08-25T14:02:27.123+02:00","severity":"ERROR","verbosity":2,"subsystem":"db","message":"quote \" backslash \\ newline \n tab \t bell \u0007"}`,
+		},
+		{
+			precision: 9,
+			line:      "invalid \xff utf-8 \u2028 ěščř",
+			expected:  `{"system":"testlog","time":"2018-08-25T14:02:27.123456789+02:00","severity":"ERROR","verbosity":2,"subsystem":"","message":"invalid \ufffd utf-8 \u2028 ěščř"}`,
+		},
+		{
+			precision: 0,
+			line:      "fields",
+			fields: []Field{
+				{"str", "value"},
+				{"int", -5},
+				{"bool", true},
+				{"err", errors.New("failed")},
+				{"duration", 2 * time.Second},
+				{"nil", nil},
+				{"list", []int{1, 2}},
+			},
+			expected: `{"system":"testlog","time":"2018-08-25T14:02:27+02:00","severity":"ERROR","verbosity":2,"subsystem":"","message":"fields","fields":{"str":"value","int":-5,"bool":true,"err":"failed","duration":"2s","nil":null,"list":[1,2]}}`,
+		},
+		{
+			precision: 0,
+			line:      "nil pointers",
+			fields: []Field{
+				{"url", (*url.URL)(nil)},
+				{"err", error((*pointerError)(nil))},
+			},
+			expected: `{"system":"testlog","time":"2018-08-25T14:02:27+02:00","severity":"ERROR","verbosity":2,"subsystem":"","message":"nil pointers","fields":{"url":"<nil>","err":"<nil>"}}`,
+		},
+		{
+			precision: 0,
+			line:      "marshalers",
+			fields: []Field{
+				{"time", now},
+				{"json", &jsonStringer{value: 3}},
+				{"nil", (*jsonStringer)(nil)},
+			},
+			expected: `{"system":"testlog","time":"2018-08-25T14:02:27+02:00","severity":"ERROR","verbosity":2,"subsystem":"","message":"marshalers","fields":{"time":"2018-08-25T14:02:27.123456789+02:00","json":{"value":3},"nil":null}}`,
+		},
+	}
+
+	for i, test := range tests {
+		formatter := NewLineFormatterJSON(
+			LineFormatterJSONOptions{TimePrecision: test.precision}).(FieldsLineFormatter)
+		writer := &bufferWriter{}
+		formatter.FormatLineFields(
+			writer, now, "testlog", test.subsystem, Error, 2, test.line, test.fields)
+		line := writer.String()
+		if line != test.expected+"\n" {
+			t.Errorf("test %d: unexpected line:\n%s", i, line)
+		}
+		if !json.Valid([]byte(line)) {
+			t.Errorf("test %d: invalid JSON", i)
+		}
+		if writer.colored {
+			t.Errorf("test %d: the color has been changed", i)
+		}
+	}
+}
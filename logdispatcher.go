@@ -2,7 +2,11 @@ package goolog2
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Dispatch a log object into the logger objects
@@ -26,11 +30,26 @@ type LogDispatcher interface {
 		verbosity Verbosity,
 		object interface{})
 
+	// Check whether a message would be logged by any logger
+	//
+	// The check is cheap (usually just a couple of atomic loads), hence
+	// it can be used to skip expensive construction of messages.
+	//
+	// Parameters:
+	//     subsystem: ID of logging subsystem
+	//     severity: severity of the log message
+	//     verbosity: verbosity of the log message
+	Enabled(
+		subsystem Subsystem,
+		severity Severity,
+		verbosity Verbosity) bool
+
 	// Add new logger
 	//
 	// Parameters:
 	//     name: a unique name of the logger
-	//     subsystem: ID of logging subsystem
+	//     subsystem: pattern of logged subsystems. Empty pattern matches
+	//         all subsystems. See the Subsystem type.
 	//     severity: maximal severity of the logger
 	//     verbosity: maximal verbosity of the logger
 	//     logger: the logger object
@@ -40,19 +59,221 @@ type LogDispatcher interface {
 		severities SeverityMask,
 		verbosity Verbosity,
 		logger Logger)
+
+	// Remove a logger
+	//
+	// The logger is destroyed after all running LogObject calls finish.
+	//
+	// Parameters:
+	//     name: name of the logger
+	// Returns:
+	//     false if there is no logger of the name
+	RemoveLogger(
+		name string) bool
+
+	// Change verbosity of a logger
+	//
+	// Parameters:
+	//     name: name of the logger
+	//     verbosity: new maximal verbosity of the logger
+	// Returns:
+	//     false if there is no logger of the name
+	SetLoggerVerbosity(
+		name string,
+		verbosity Verbosity) bool
+
+	// Change severities of a logger
+	//
+	// Parameters:
+	//     name: name of the logger
+	//     severities: new mask of severities of the logger
+	// Returns:
+	//     false if there is no logger of the name
+	SetLoggerSeverities(
+		name string,
+		severities SeverityMask) bool
+
+	// Change subsystem of a logger
+	//
+	// Parameters:
+	//     name: name of the logger
+	//     subsystem: new subsystem pattern of the logger. Can be empty.
+	// Returns:
+	//     false if there is no logger of the name
+	SetLoggerSubsystem(
+		name string,
+		subsystem Subsystem) bool
+
+	// Override severities and verbosity of a logger for a subsystem
+	//
+	// The override is applied to the subsystem and its nested subsystems
+	// (the most specific override wins). The messages must still match
+	// the subsystem pattern of the logger.
+	//
+	// Parameters:
+	//     name: name of the logger
+	//     subsystem: the subsystem
+	//     severities: mask of severities logged for the subsystem
+	//     verbosity: maximal verbosity logged for the subsystem
+	// Returns:
+	//     false if there is no logger of the name
+	SetLoggerSubsystemVerbosity(
+		name string,
+		subsystem Subsystem,
+		severities SeverityMask,
+		verbosity Verbosity) bool
+
+	// Remove an override set by SetLoggerSubsystemVerbosity
+	//
+	// Parameters:
+	//     name: name of the logger
+	//     subsystem: the subsystem
+	// Returns:
+	//     false if there is no logger of the name or no such override
+	ClearLoggerSubsystemVerbosity(
+		name string,
+		subsystem Subsystem) bool
+
+	// Get a snapshot of current loggers
+	//
+	// Returns:
+	//     descriptions of the loggers sorted by their names
+	ListLoggers() []LoggerInfo
+
+	// Set handler of errors of the loggers
+	//
+	// The handler is passed to all current and future loggers
+	// implementing the ErrorReporter interface. The rate of reported
+	// errors is limited to one error per second.
+	//
+	// Parameters:
+	//     handler: the handler. Nil disables reporting of the errors.
+	SetErrorHandler(
+		handler ErrorHandler)
+
+	// Change several loggers atomically
+	//
+	// Concurrent LogObject calls see either the loggers before
+	// the change or after it, never a mix. Removed and replaced
+	// loggers are destroyed after the change is finished.
+	//
+	// Parameters:
+	//     functor: a function changing the loggers. The set is valid
+	//         only during the call. The function must not log
+	//         into the dispatcher.
+	Reconfigure(
+		functor func(loggers LoggerSet))
+}
+
+// Set of loggers changed by LogDispatcher.Reconfigure
+//
+// The methods have the same meaning as the methods of the dispatcher.
+type LoggerSet interface {
+	AddLogger(
+		name string,
+		subsystem Subsystem,
+		severities SeverityMask,
+		verbosity Verbosity,
+		logger Logger)
+
+	RemoveLogger(
+		name string) bool
+
+	SetLoggerVerbosity(
+		name string,
+		verbosity Verbosity) bool
+
+	SetLoggerSeverities(
+		name string,
+		severities SeverityMask) bool
+
+	SetLoggerSubsystem(
+		name string,
+		subsystem Subsystem) bool
+
+	SetLoggerSubsystemVerbosity(
+		name string,
+		subsystem Subsystem,
+		severities SeverityMask,
+		verbosity Verbosity) bool
+
+	ClearLoggerSubsystemVerbosity(
+		name string,
+		subsystem Subsystem) bool
+
+	ListLoggers() []LoggerInfo
+}
+
+// Description of a registered logger
+type LoggerInfo struct {
+	Name       string
+	Subsystem  Subsystem
+	Severities SeverityMask
+	Verbosity  Verbosity
 }
 
 type logDispatcherRecord struct {
 	subsystem  Subsystem
+	filter     *subsystemFilter
 	severities SeverityMask
 	verbosity  Verbosity
+	overrides  map[Subsystem]subsystemLevels
 	logger     Logger
 }
 
+// Severities and verbosity overridden for a subsystem
+type subsystemLevels struct {
+	severities SeverityMask
+	verbosity  Verbosity
+}
+
+// Check whether the logger accepts a message
+func (this *logDispatcherRecord) accepts(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) bool {
+	severities, maxVerbosity := this.levels(subsystem)
+	return (uint32(severities)&uint32(severity)) != 0 &&
+		verbosity <= maxVerbosity &&
+		this.filter.match(subsystem)
+}
+
+// Get severities and verbosity of the record for a subsystem
+func (this *logDispatcherRecord) levels(
+	subsystem Subsystem,
+) (SeverityMask, Verbosity) {
+	if len(this.overrides) != 0 {
+		/* -- the subsystem itself or the closest parent */
+		node := string(subsystem)
+		for {
+			if levels, exists := this.overrides[Subsystem(node)]; exists {
+				return levels.severities, levels.verbosity
+			}
+			index := strings.LastIndexByte(node, '/')
+			if index < 0 {
+				break
+			}
+			node = node[:index]
+		}
+	}
+	return this.severities, this.verbosity
+}
+
+/* -- number of single-bit severities */
+const severityCount = 5
+
 type logDispatcher struct {
-	system  string
-	loggers map[string]*logDispatcherRecord
-	mutex   sync.RWMutex
+	system       string
+	loggers      map[string]*logDispatcherRecord
+	errorHandler ErrorHandler
+	mutex        sync.RWMutex
+
+	/* -- Summary of the loggers recomputed after every change. For every
+	   severity there is the maximal verbosity of any logger and the maximal
+	   verbosity of loggers accepting all subsystems without overrides. */
+	maxVerbosity  [severityCount]uint32
+	maxUnfiltered [severityCount]uint32
 }
 
 // Create new log dispatcher
@@ -84,9 +305,7 @@ func (this *logDispatcher) LogObject(
 	this.mutex.RLock()
 	defer this.mutex.RUnlock()
 	for _, record := range this.loggers {
-		if (record.subsystem == "" || record.subsystem == subsystem) &&
-			(uint32(record.severities)&uint32(severity)) != 0 &&
-			verbosity <= record.verbosity {
+		if record.accepts(subsystem, severity, verbosity) {
 			/* -- the conditions match, log the object */
 			record.logger.LogObject(
 				this.system, subsystem, severity, verbosity, object)
@@ -94,6 +313,75 @@ func (this *logDispatcher) LogObject(
 	}
 }
 
+func (this *logDispatcher) Enabled(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) bool {
+	index := severityIndex(severity)
+	if index >= 0 {
+		if uint32(verbosity) > atomic.LoadUint32(&this.maxVerbosity[index]) {
+			return false
+		}
+		if uint32(verbosity) <= atomic.LoadUint32(&this.maxUnfiltered[index]) {
+			return true
+		}
+	}
+
+	/* -- the exact check */
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	for _, record := range this.loggers {
+		if record.accepts(subsystem, severity, verbosity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get index of a single-bit severity
+//
+// Returns:
+//     the index or -1 if the severity isn't a single severity
+func severityIndex(
+	severity Severity,
+) int {
+	for index := 0; index < severityCount; index++ {
+		if severity == Severity(1)<<uint(index) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Recompute the summary of the loggers
+//
+// The write lock must be held.
+func (this *logDispatcher) updateSummary() {
+	for index := 0; index < severityCount; index++ {
+		mask := SeverityMask(1) << uint(index)
+		var maxVerbosity, maxUnfiltered uint32
+		for _, record := range this.loggers {
+			if record.severities&mask != 0 {
+				if uint32(record.verbosity) > maxVerbosity {
+					maxVerbosity = uint32(record.verbosity)
+				}
+				if record.filter == nil && len(record.overrides) == 0 &&
+					uint32(record.verbosity) > maxUnfiltered {
+					maxUnfiltered = uint32(record.verbosity)
+				}
+			}
+			for _, levels := range record.overrides {
+				if levels.severities&mask != 0 && uint32(levels.verbosity) > maxVerbosity {
+					maxVerbosity = uint32(levels.verbosity)
+				}
+			}
+		}
+		atomic.StoreUint32(&this.maxVerbosity[index], maxVerbosity)
+		atomic.StoreUint32(&this.maxUnfiltered[index], maxUnfiltered)
+	}
+}
+
 func (this *logDispatcher) AddLogger(
 	name string,
 	subsystem Subsystem,
@@ -101,14 +389,273 @@ func (this *logDispatcher) AddLogger(
 	verbosity Verbosity,
 	logger Logger,
 ) {
-	this.mutex.Lock()
-	defer this.mutex.Unlock()
-	this.loggers[name] = &logDispatcherRecord{
+	this.Reconfigure(func(loggers LoggerSet) {
+		loggers.AddLogger(name, subsystem, severities, verbosity, logger)
+	})
+}
+
+func (this *logDispatcher) RemoveLogger(
+	name string,
+) bool {
+	/* -- The write lock waits for all running LogObject calls. No one
+	   can reach the logger after it's unlocked. */
+	removed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		removed = loggers.RemoveLogger(name)
+	})
+	return removed
+}
+
+func (this *logDispatcher) SetLoggerVerbosity(
+	name string,
+	verbosity Verbosity,
+) bool {
+	changed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		changed = loggers.SetLoggerVerbosity(name, verbosity)
+	})
+	return changed
+}
+
+func (this *logDispatcher) SetLoggerSeverities(
+	name string,
+	severities SeverityMask,
+) bool {
+	changed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		changed = loggers.SetLoggerSeverities(name, severities)
+	})
+	return changed
+}
+
+func (this *logDispatcher) SetLoggerSubsystem(
+	name string,
+	subsystem Subsystem,
+) bool {
+	changed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		changed = loggers.SetLoggerSubsystem(name, subsystem)
+	})
+	return changed
+}
+
+func (this *logDispatcher) SetLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+) bool {
+	changed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		changed = loggers.SetLoggerSubsystemVerbosity(
+			name, subsystem, severities, verbosity)
+	})
+	return changed
+}
+
+func (this *logDispatcher) ClearLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+) bool {
+	changed := false
+	this.Reconfigure(func(loggers LoggerSet) {
+		changed = loggers.ClearLoggerSubsystemVerbosity(name, subsystem)
+	})
+	return changed
+}
+
+func (this *logDispatcher) ListLoggers() []LoggerInfo {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	return this.listLoggers()
+}
+
+func (this *logDispatcher) listLoggers() []LoggerInfo {
+	infos := make([]LoggerInfo, 0, len(this.loggers))
+	for name, record := range this.loggers {
+		infos = append(infos, LoggerInfo{
+			Name:       name,
+			Subsystem:  record.subsystem,
+			Severities: record.severities,
+			Verbosity:  record.verbosity,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+func (this *logDispatcher) Reconfigure(
+	functor func(loggers LoggerSet),
+) {
+	batch := &loggerBatch{dispatcher: this}
+	var handler ErrorHandler
+	func() {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		functor(batch)
+		this.updateSummary()
+		handler = this.errorHandler
+	}()
+
+	/* -- the new loggers get the error handler, the removed ones are
+	   destroyed out of the lock */
+	if handler != nil {
+		for _, logger := range batch.added {
+			if reporter, ok := logger.(ErrorReporter); ok {
+				reporter.SetErrorHandler(handler)
+			}
+		}
+	}
+	for _, logger := range batch.removed {
+		logger.Destroy()
+	}
+}
+
+// Changes of the loggers made under the dispatcher's lock
+type loggerBatch struct {
+	dispatcher *logDispatcher
+	added      []Logger
+	removed    []Logger
+}
+
+func (this *loggerBatch) AddLogger(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+	logger Logger,
+) {
+	old := this.dispatcher.loggers[name]
+	this.dispatcher.loggers[name] = &logDispatcherRecord{
 		subsystem:  subsystem,
+		filter:     newSubsystemFilter(subsystem),
 		severities: severities,
 		verbosity:  verbosity,
 		logger:     logger,
 	}
+	this.added = append(this.added, logger)
+
+	/* -- a replaced logger is destroyed the same way as a removed one */
+	if old != nil && old.logger != logger {
+		this.removed = append(this.removed, old.logger)
+	}
+	/* -- the logger could be removed in the same batch */
+	for i, removed := range this.removed {
+		if removed == logger {
+			this.removed = append(this.removed[:i], this.removed[i+1:]...)
+			break
+		}
+	}
+}
+
+func (this *loggerBatch) RemoveLogger(
+	name string,
+) bool {
+	record, exists := this.dispatcher.loggers[name]
+	if !exists {
+		return false
+	}
+	delete(this.dispatcher.loggers, name)
+	this.removed = append(this.removed, record.logger)
+	return true
+}
+
+func (this *loggerBatch) updateLogger(
+	name string,
+	functor func(record *logDispatcherRecord),
+) bool {
+	record, exists := this.dispatcher.loggers[name]
+	if !exists {
+		return false
+	}
+	functor(record)
+	return true
+}
+
+func (this *loggerBatch) SetLoggerVerbosity(
+	name string,
+	verbosity Verbosity,
+) bool {
+	return this.updateLogger(name, func(record *logDispatcherRecord) {
+		record.verbosity = verbosity
+	})
+}
+
+func (this *loggerBatch) SetLoggerSeverities(
+	name string,
+	severities SeverityMask,
+) bool {
+	return this.updateLogger(name, func(record *logDispatcherRecord) {
+		record.severities = severities
+	})
+}
+
+func (this *loggerBatch) SetLoggerSubsystem(
+	name string,
+	subsystem Subsystem,
+) bool {
+	return this.updateLogger(name, func(record *logDispatcherRecord) {
+		record.subsystem = subsystem
+		record.filter = newSubsystemFilter(subsystem)
+	})
+}
+
+func (this *loggerBatch) SetLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+	severities SeverityMask,
+	verbosity Verbosity,
+) bool {
+	return this.updateLogger(name, func(record *logDispatcherRecord) {
+		if record.overrides == nil {
+			record.overrides = make(map[Subsystem]subsystemLevels)
+		}
+		record.overrides[subsystem] = subsystemLevels{severities, verbosity}
+	})
+}
+
+func (this *loggerBatch) ClearLoggerSubsystemVerbosity(
+	name string,
+	subsystem Subsystem,
+) bool {
+	cleared := false
+	this.updateLogger(name, func(record *logDispatcherRecord) {
+		if _, exists := record.overrides[subsystem]; exists {
+			cleared = true
+			delete(record.overrides, subsystem)
+		}
+	})
+	return cleared
+}
+
+func (this *loggerBatch) ListLoggers() []LoggerInfo {
+	return this.dispatcher.listLoggers()
+}
+
+func (this *logDispatcher) SetErrorHandler(
+	handler ErrorHandler,
+) {
+	if handler != nil {
+		handler = NewRateLimitedErrorHandler(handler, time.Second)
+	}
+
+	this.mutex.Lock()
+	this.errorHandler = handler
+	var reporters []ErrorReporter
+	for _, record := range this.loggers {
+		if reporter, ok := record.logger.(ErrorReporter); ok {
+			reporters = append(reporters, reporter)
+		}
+	}
+	this.mutex.Unlock()
+
+	/* -- The loggers can report pending errors immediately. The handler
+	   is invoked out of the lock. */
+	for _, reporter := range reporters {
+		reporter.SetErrorHandler(handler)
+	}
 }
 
 // Log a logging object
@@ -138,6 +685,9 @@ func DispatcherLogMessage(
 	verbosity Verbosity,
 	message string,
 ) {
+	if !log.Enabled(subsystem, severity, verbosity) {
+		return
+	}
 	log.LogObject(
 		subsystem,
 		severity,
@@ -163,9 +713,90 @@ func DispatcherLogMessagef(
 	format string,
 	args ...interface{},
 ) {
+	if !log.Enabled(subsystem, severity, verbosity) {
+		return
+	}
+	/* -- The copy keeps the caller's slice on the stack, hence a disabled
+	   message doesn't allocate. */
+	log.LogObject(
+		subsystem,
+		severity,
+		verbosity,
+		&formattedLogMessageObject{format, append([]interface{}(nil), args...)})
+}
+
+type lazyLogMessageObject struct {
+	producer func() string
+	message  string
+	produced bool
+}
+
+func (this *lazyLogMessageObject) GetLogLine() string {
+	/* -- the loggers are invoked sequentially, the message is produced once */
+	if !this.produced {
+		this.message = this.producer()
+		this.produced = true
+	}
+	return this.message
+}
+
+// Log a text message produced only if it's logged
+//
+// Parameters:
+//     producer: a function producing the message. It's invoked at most
+//         once and only if a logger accepts the message.
+func DispatcherLogMessagel(
+	log LogDispatcher,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	producer func() string,
+) {
+	if !log.Enabled(subsystem, severity, verbosity) {
+		return
+	}
+	log.LogObject(
+		subsystem,
+		severity,
+		verbosity,
+		&lazyLogMessageObject{producer: producer})
+}
+
+// Log a message with structured fields
+func DispatcherLogFields(
+	log LogDispatcher,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	fields ...Field,
+) {
+	if !log.Enabled(subsystem, severity, verbosity) {
+		return
+	}
+	log.LogObject(
+		subsystem,
+		severity,
+		verbosity,
+		&fieldsMessageObject{message, fields})
+}
+
+// Log a message with structured fields specified as alternating
+// keys and values
+func DispatcherLogMessagekv(
+	log LogDispatcher,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	keyvals ...interface{},
+) {
+	if !log.Enabled(subsystem, severity, verbosity) {
+		return
+	}
 	log.LogObject(
 		subsystem,
 		severity,
 		verbosity,
-		&formattedLogMessageObject{format, args})
+		&fieldsMessageObject{message, keyValuesToFields(keyvals)})
 }
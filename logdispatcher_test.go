@@ -2,17 +2,20 @@ package goolog2_test
 
 import (
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	. "github.com/Staon/goolog2"
 )
 
 type testLogger struct {
-	touched bool
+	touched   bool
+	destroyed bool
 }
 
 func (this *testLogger) Destroy() {
-	/* -- nothing to do */
+	this.destroyed = true
 }
 
 func (this *testLogger) LogObject(
@@ -223,3 +226,308 @@ func TestLogDispatcher(t *testing.T) {
 
 	Destroy()
 }
+
+func TestLogDispatcherReconfiguration(t *testing.T) {
+	Init("testlog")
+	defer Destroy()
+
+	first := &testLogger{}
+	second := &testLogger{}
+	AddLogger("first", "", MaskAll, 1, first)
+	AddLogger("second", "test", MaskError, 2, second)
+
+	/* -- snapshot of the loggers */
+	infos := ListLoggers()
+	if len(infos) != 2 ||
+		infos[0] != (LoggerInfo{"first", "", MaskAll, 1}) ||
+		infos[1] != (LoggerInfo{"second", "test", MaskError, 2}) {
+		t.Errorf("unexpected list of loggers: %v", infos)
+	}
+
+	/* -- verbosity */
+	Info5("")
+	if !first.Check(false) {
+		t.Errorf("verbosity 5 shouldn't be logged")
+	}
+	if !SetLoggerVerbosity("first", 5) {
+		t.Errorf("the logger should be found")
+	}
+	Info5("")
+	if !first.Check(true) {
+		t.Errorf("verbosity 5 should be logged")
+	}
+
+	/* -- severities */
+	if !SetLoggerSeverities("first", MaskError) {
+		t.Errorf("the logger should be found")
+	}
+	Info1("")
+	if !first.Check(false) {
+		t.Errorf("info shouldn't be logged")
+	}
+
+	/* -- subsystem */
+	Error1s("other", "")
+	if !second.Check(false) {
+		t.Errorf("subsystem other shouldn't be logged")
+	}
+	if !SetLoggerSubsystem("second", "other") {
+		t.Errorf("the logger should be found")
+	}
+	Error1s("other", "")
+	if !second.Check(true) {
+		t.Errorf("subsystem other should be logged")
+	}
+
+	first.Check(true)
+
+	/* -- removing */
+	if !RemoveLogger("first") || !first.destroyed {
+		t.Errorf("the logger should be removed and destroyed")
+	}
+	Error1("")
+	if !first.Check(false) {
+		t.Errorf("removed logger shouldn't be used")
+	}
+	if len(ListLoggers()) != 1 {
+		t.Errorf("only one logger should be left")
+	}
+
+	/* -- unknown loggers */
+	if RemoveLogger("first") || SetLoggerVerbosity("unknown", 1) ||
+		SetLoggerSeverities("unknown", MaskAll) ||
+		SetLoggerSubsystem("unknown", "") {
+		t.Errorf("unknown logger shouldn't be found")
+	}
+}
+
+type concurrentTestLogger struct {
+	destroyed int32
+	failed    int32
+}
+
+func (this *concurrentTestLogger) Destroy() {
+	atomic.StoreInt32(&this.destroyed, 1)
+}
+
+func (this *concurrentTestLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	runtime.Gosched()
+	if atomic.LoadInt32(&this.destroyed) != 0 {
+		atomic.StoreInt32(&this.failed, 1)
+	}
+}
+
+func TestLogDispatcherConcurrentRemoving(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+
+	loggers := make([]*concurrentTestLogger, 50)
+	for i := range loggers {
+		loggers[i] = &concurrentTestLogger{}
+		dispatcher.AddLogger(string(rune('A'+i)), "", MaskAll, 5, loggers[i])
+	}
+
+	/* -- log from several goroutines while the loggers are being removed */
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					DispatcherLogMessage(dispatcher, "", Info, 1, "message")
+				}
+			}
+		}()
+	}
+	for i := range loggers {
+		dispatcher.SetLoggerVerbosity(string(rune('A'+i)), 2)
+		dispatcher.RemoveLogger(string(rune('A' + i)))
+	}
+	close(stop)
+	wg.Wait()
+
+	for i, logger := range loggers {
+		if atomic.LoadInt32(&logger.failed) != 0 {
+			t.Errorf("logger %d has been used after destruction", i)
+		}
+	}
+}
+
+func TestLogDispatcherBatch(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+
+	removed := &testLogger{}
+	replaced := &testLogger{}
+	kept := &testLogger{}
+	added := &testLogger{}
+	dispatcher.AddLogger("removed", "", MaskAll, 1, removed)
+	dispatcher.AddLogger("replaced", "", MaskAll, 1, replaced)
+	dispatcher.AddLogger("kept", "", MaskAll, 1, kept)
+
+	dispatcher.Reconfigure(func(loggers LoggerSet) {
+		if !loggers.RemoveLogger("removed") || loggers.RemoveLogger("unknown") {
+			t.Errorf("unexpected result of removing")
+		}
+		loggers.AddLogger("replaced", "", MaskAll, 2, added)
+		loggers.SetLoggerVerbosity("kept", 3)
+		if len(loggers.ListLoggers()) != 2 {
+			t.Errorf("unexpected loggers in the batch")
+		}
+		/* -- the loggers are destroyed after the batch */
+		if removed.destroyed || replaced.destroyed {
+			t.Errorf("the logger is destroyed inside the batch")
+		}
+	})
+	if !removed.destroyed || !replaced.destroyed || kept.destroyed || added.destroyed {
+		t.Errorf("unexpected destroyed loggers")
+	}
+	infos := dispatcher.ListLoggers()
+	if len(infos) != 2 ||
+		infos[0] != (LoggerInfo{"kept", "", MaskAll, 3}) ||
+		infos[1] != (LoggerInfo{"replaced", "", MaskAll, 2}) {
+		t.Errorf("unexpected loggers: %v", infos)
+	}
+}
+
+func TestLogDispatcherSubsystemVerbosity(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	logger := &testLogger{}
+	dispatcher.AddLogger("logger", "", MaskStd, 2, logger)
+
+	if !dispatcher.SetLoggerSubsystemVerbosity("logger", "db", MaskAll, 5) ||
+		!dispatcher.SetLoggerSubsystemVerbosity("logger", "db/query", MaskError, 1) ||
+		dispatcher.SetLoggerSubsystemVerbosity("unknown", "db", MaskAll, 5) {
+		t.Fatalf("unexpected result of setting of the overrides")
+	}
+
+	tests := []struct {
+		subsystem Subsystem
+		severity  Severity
+		verbosity Verbosity
+		expected  bool
+	}{
+		{"", Info, 2, true},
+		{"", Info, 3, false},
+		{"", Debug, 1, false},
+		{"db", Info, 5, true},
+		{"db", Debug, 5, true},
+		{"db/pool", Debug, 4, true},
+		{"db/pool", Info, 6, false},
+		{"db/query", Error, 1, true},
+		{"db/query", Error, 2, false},
+		{"db/query/slow", Info, 1, false},
+		{"dbx", Info, 3, false},
+	}
+	for _, test := range tests {
+		DispatcherLogMessage(dispatcher, test.subsystem, test.severity, test.verbosity, "message")
+		if !logger.Check(test.expected) {
+			t.Errorf("%q, %s, %d: unexpected result", test.subsystem, test.severity, test.verbosity)
+		}
+	}
+
+	/* -- the nested override is removed, the parent applies again */
+	if !dispatcher.ClearLoggerSubsystemVerbosity("logger", "db/query") ||
+		dispatcher.ClearLoggerSubsystemVerbosity("logger", "db/query") {
+		t.Errorf("unexpected result of clearing of the override")
+	}
+	DispatcherLogMessage(dispatcher, "db/query", Debug, 5, "message")
+	if !logger.Check(true) {
+		t.Errorf("the parent override isn't applied")
+	}
+}
+
+func TestLogDispatcherEnabled(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	dispatcher.AddLogger("std", "", MaskStd, 2, &testLogger{})
+	dispatcher.AddLogger("db", "db,!db/query", MaskDebug, 3, &testLogger{})
+	dispatcher.SetLoggerSubsystemVerbosity("std", "net", MaskError, 4)
+
+	tests := []struct {
+		subsystem Subsystem
+		severity  Severity
+		verbosity Verbosity
+		expected  bool
+	}{
+		{"", Info, 2, true},
+		{"", Info, 3, false},
+		{"", Debug, 1, false},
+		{"db", Debug, 3, true},
+		{"db/pool", Debug, 3, true},
+		{"db/query", Debug, 1, false},
+		{"db", Debug, 4, false},
+		{"net", Error, 4, true},
+		{"net", Info, 1, false},
+		{"net/tcp", Error, 4, true},
+		{"", Error, 4, false},
+	}
+	for _, test := range tests {
+		if dispatcher.Enabled(test.subsystem, test.severity, test.verbosity) != test.expected {
+			t.Errorf("%q, %s, %d: unexpected result", test.subsystem, test.severity, test.verbosity)
+		}
+	}
+
+	/* -- the summary follows changes of the loggers */
+	dispatcher.SetLoggerVerbosity("std", 5)
+	if !dispatcher.Enabled("", Info, 5) {
+		t.Errorf("the verbosity change isn't reflected")
+	}
+	dispatcher.RemoveLogger("std")
+	dispatcher.RemoveLogger("db")
+	if dispatcher.Enabled("", Critical, 1) {
+		t.Errorf("nothing should be enabled without loggers")
+	}
+}
+
+func TestLogDispatcherDisabledAllocations(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	dispatcher.AddLogger("logger", "", MaskStd, 2, &testLogger{})
+
+	value := 42
+	allocs := testing.AllocsPerRun(100, func() {
+		DispatcherLogMessagef(dispatcher, "", Debug, 5, "value %d", value)
+	})
+	if allocs != 0 {
+		t.Errorf("disabled message allocates: %v", allocs)
+	}
+}
+
+func TestLogDispatcherLazyMessage(t *testing.T) {
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	first := &recordingLogger{}
+	second := &recordingLogger{}
+	dispatcher.AddLogger("first", "", MaskStd, 2, first)
+	dispatcher.AddLogger("second", "", MaskStd, 2, second)
+
+	calls := 0
+	producer := func() string {
+		calls++
+		return "message"
+	}
+	log := NewLog(dispatcher, "")
+	log.Debug5l(producer)
+	if calls != 0 || first.count != 0 {
+		t.Errorf("the producer of a disabled message is invoked")
+	}
+	log.Info2l(producer)
+	if calls != 1 {
+		t.Errorf("the producer is invoked %d times", calls)
+	}
+	first.Check(t, "", Info, 2, "message")
+	second.Check(t, "", Info, 2, "message")
+}
@@ -0,0 +1,1130 @@
+package goolog2
+
+import ()
+
+// Logging handle
+//
+// The handle wraps a log dispatcher and a default subsystem. It offers
+// the same set of convenient logging functions as the global log. Hence
+// a library can receive its own handle instead of using the global log.
+type Log struct {
+	dispatcher LogDispatcher
+	subsystem  Subsystem
+}
+
+// Create new logging handle
+//
+// Parameters:
+//     dispatcher: the log dispatcher
+//     subsystem: default subsystem of the messages. Can be empty.
+// Returns:
+//     the handle
+func NewLog(
+	dispatcher LogDispatcher,
+	subsystem Subsystem,
+) *Log {
+	return &Log{
+		dispatcher: dispatcher,
+		subsystem:  subsystem,
+	}
+}
+
+// Get a handle of the global log
+//
+// The handle follows the global log even if it's initialized again.
+// Destroy() of its dispatcher does nothing, the global log is destroyed
+// by the global function Destroy().
+func Global() *Log {
+	return NewLog(&globalDispatcher{}, "")
+}
+
+// Get a copy of the handle with another default subsystem
+func (this *Log) WithSubsystem(
+	subsystem Subsystem,
+) *Log {
+	return NewLog(this.dispatcher, subsystem)
+}
+
+// Get the wrapped dispatcher
+func (this *Log) Dispatcher() LogDispatcher {
+	return this.dispatcher
+}
+
+// Get the default subsystem
+func (this *Log) Subsystem() Subsystem {
+	return this.subsystem
+}
+
+// Check whether a message in the default subsystem would be logged
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+func (this *Log) Enabled(
+	severity Severity,
+	verbosity Verbosity,
+) bool {
+	return this.dispatcher.Enabled(this.subsystem, severity, verbosity)
+}
+
+// Log a logging object in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     object: logging object
+func (this *Log) LogObject(
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	DispatcherLogObject(this.dispatcher, this.subsystem, severity, verbosity, object)
+}
+
+// Log a text message in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     message: the message
+func (this *Log) LogMessage(
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, severity, verbosity, message)
+}
+
+// Log a formatted text message in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     format: printf-like format of the message
+//     args: arguments of the message
+func (this *Log) LogMessagef(
+	severity Severity,
+	verbosity Verbosity,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(
+		this.dispatcher, this.subsystem, severity, verbosity, format, args...)
+}
+
+// Log a text message produced only if it's logged in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     producer: a function producing the message
+func (this *Log) LogMessagel(
+	severity Severity,
+	verbosity Verbosity,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, severity, verbosity, producer)
+}
+
+// Log a message with structured fields in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     message: the message
+//     fields: structured fields of the message
+func (this *Log) LogFields(
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	fields ...Field,
+) {
+	DispatcherLogFields(
+		this.dispatcher, this.subsystem, severity, verbosity, message, fields...)
+}
+
+// Log a message with structured fields in the default subsystem
+//
+// Parameters:
+//     severity: logging severity
+//     verbosity: logging verbosity
+//     message: the message
+//     keyvals: alternating keys and values of the fields
+func (this *Log) LogMessagekv(
+	severity Severity,
+	verbosity Verbosity,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(
+		this.dispatcher, this.subsystem, severity, verbosity, message, keyvals...)
+}
+
+// There are a set of convenient logging methods. Their names follow
+// the same pattern as the global functions:
+//     <severity><verbosity>[f|kv|l][s]
+//
+//     f .... the message is formatted
+//     kv ... the message is followed by alternating keys and values
+//            of structured fields
+//     l .... the message is produced by a function invoked only
+//            if the message is logged
+//     s .... a subsystem is specified. Otherwise the default subsystem
+//            of the handle is used.
+
+/* -- critical errors */
+func (this *Log) Critical1(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Critical, 1, message)
+}
+
+func (this *Log) Critical1s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Critical, 1, message)
+}
+
+func (this *Log) Critical1f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Critical, 1, format, args...)
+}
+
+func (this *Log) Critical1fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Critical, 1, format, args...)
+}
+
+func (this *Log) Critical1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Critical, 1, message, keyvals...)
+}
+
+func (this *Log) Critical1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Critical, 1, message, keyvals...)
+}
+
+func (this *Log) Critical1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Critical, 1, producer)
+}
+
+func (this *Log) Critical1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Critical, 1, producer)
+}
+
+/* -- errors */
+func (this *Log) Error1(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Error, 1, message)
+}
+
+func (this *Log) Error1s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Error, 1, message)
+}
+
+func (this *Log) Error1f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Error, 1, format, args...)
+}
+
+func (this *Log) Error1fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Error, 1, format, args...)
+}
+
+func (this *Log) Error1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Error, 1, message, keyvals...)
+}
+
+func (this *Log) Error1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Error, 1, message, keyvals...)
+}
+
+func (this *Log) Error1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Error, 1, producer)
+}
+
+func (this *Log) Error1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Error, 1, producer)
+}
+
+func (this *Log) Error2(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Error, 2, message)
+}
+
+func (this *Log) Error2s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Error, 2, message)
+}
+
+func (this *Log) Error2f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Error, 2, format, args...)
+}
+
+func (this *Log) Error2fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Error, 2, format, args...)
+}
+
+func (this *Log) Error2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Error, 2, message, keyvals...)
+}
+
+func (this *Log) Error2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Error, 2, message, keyvals...)
+}
+
+func (this *Log) Error2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Error, 2, producer)
+}
+
+func (this *Log) Error2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Error, 2, producer)
+}
+
+func (this *Log) Error3(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Error, 3, message)
+}
+
+func (this *Log) Error3s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Error, 3, message)
+}
+
+func (this *Log) Error3f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Error, 3, format, args...)
+}
+
+func (this *Log) Error3fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Error, 3, format, args...)
+}
+
+func (this *Log) Error3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Error, 3, message, keyvals...)
+}
+
+func (this *Log) Error3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Error, 3, message, keyvals...)
+}
+
+func (this *Log) Error3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Error, 3, producer)
+}
+
+func (this *Log) Error3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Error, 3, producer)
+}
+
+func (this *Log) Error4(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Error, 4, message)
+}
+
+func (this *Log) Error4s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Error, 4, message)
+}
+
+func (this *Log) Error4f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Error, 4, format, args...)
+}
+
+func (this *Log) Error4fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Error, 4, format, args...)
+}
+
+func (this *Log) Error4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Error, 4, message, keyvals...)
+}
+
+func (this *Log) Error4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Error, 4, message, keyvals...)
+}
+
+func (this *Log) Error4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Error, 4, producer)
+}
+
+func (this *Log) Error4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Error, 4, producer)
+}
+
+/* -- warnings */
+func (this *Log) Warning1(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Warning, 1, message)
+}
+
+func (this *Log) Warning1s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Warning, 1, message)
+}
+
+func (this *Log) Warning1f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Warning, 1, format, args...)
+}
+
+func (this *Log) Warning1fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Warning, 1, format, args...)
+}
+
+func (this *Log) Warning1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Warning, 1, message, keyvals...)
+}
+
+func (this *Log) Warning1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Warning, 1, message, keyvals...)
+}
+
+func (this *Log) Warning1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Warning, 1, producer)
+}
+
+func (this *Log) Warning1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Warning, 1, producer)
+}
+
+func (this *Log) Warning2(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Warning, 2, message)
+}
+
+func (this *Log) Warning2s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Warning, 2, message)
+}
+
+func (this *Log) Warning2f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Warning, 2, format, args...)
+}
+
+func (this *Log) Warning2fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Warning, 2, format, args...)
+}
+
+func (this *Log) Warning2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Warning, 2, message, keyvals...)
+}
+
+func (this *Log) Warning2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Warning, 2, message, keyvals...)
+}
+
+func (this *Log) Warning2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Warning, 2, producer)
+}
+
+func (this *Log) Warning2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Warning, 2, producer)
+}
+
+func (this *Log) Warning3(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Warning, 3, message)
+}
+
+func (this *Log) Warning3s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Warning, 3, message)
+}
+
+func (this *Log) Warning3f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Warning, 3, format, args...)
+}
+
+func (this *Log) Warning3fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Warning, 3, format, args...)
+}
+
+func (this *Log) Warning3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Warning, 3, message, keyvals...)
+}
+
+func (this *Log) Warning3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Warning, 3, message, keyvals...)
+}
+
+func (this *Log) Warning3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Warning, 3, producer)
+}
+
+func (this *Log) Warning3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Warning, 3, producer)
+}
+
+func (this *Log) Warning4(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Warning, 4, message)
+}
+
+func (this *Log) Warning4s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Warning, 4, message)
+}
+
+func (this *Log) Warning4f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Warning, 4, format, args...)
+}
+
+func (this *Log) Warning4fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Warning, 4, format, args...)
+}
+
+func (this *Log) Warning4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Warning, 4, message, keyvals...)
+}
+
+func (this *Log) Warning4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Warning, 4, message, keyvals...)
+}
+
+func (this *Log) Warning4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Warning, 4, producer)
+}
+
+func (this *Log) Warning4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Warning, 4, producer)
+}
+
+/* -- info messages */
+func (this *Log) Info1(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Info, 1, message)
+}
+
+func (this *Log) Info1s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Info, 1, message)
+}
+
+func (this *Log) Info1f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Info, 1, format, args...)
+}
+
+func (this *Log) Info1fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Info, 1, format, args...)
+}
+
+func (this *Log) Info1kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Info, 1, message, keyvals...)
+}
+
+func (this *Log) Info1kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Info, 1, message, keyvals...)
+}
+
+func (this *Log) Info1l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Info, 1, producer)
+}
+
+func (this *Log) Info1ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Info, 1, producer)
+}
+
+func (this *Log) Info2(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Info, 2, message)
+}
+
+func (this *Log) Info2s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Info, 2, message)
+}
+
+func (this *Log) Info2f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Info, 2, format, args...)
+}
+
+func (this *Log) Info2fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Info, 2, format, args...)
+}
+
+func (this *Log) Info2kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Info, 2, message, keyvals...)
+}
+
+func (this *Log) Info2kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Info, 2, message, keyvals...)
+}
+
+func (this *Log) Info2l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Info, 2, producer)
+}
+
+func (this *Log) Info2ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Info, 2, producer)
+}
+
+func (this *Log) Info3(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Info, 3, message)
+}
+
+func (this *Log) Info3s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Info, 3, message)
+}
+
+func (this *Log) Info3f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Info, 3, format, args...)
+}
+
+func (this *Log) Info3fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Info, 3, format, args...)
+}
+
+func (this *Log) Info3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Info, 3, message, keyvals...)
+}
+
+func (this *Log) Info3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Info, 3, message, keyvals...)
+}
+
+func (this *Log) Info3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Info, 3, producer)
+}
+
+func (this *Log) Info3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Info, 3, producer)
+}
+
+func (this *Log) Info4(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Info, 4, message)
+}
+
+func (this *Log) Info4s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Info, 4, message)
+}
+
+func (this *Log) Info4f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Info, 4, format, args...)
+}
+
+func (this *Log) Info4fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Info, 4, format, args...)
+}
+
+func (this *Log) Info4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Info, 4, message, keyvals...)
+}
+
+func (this *Log) Info4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Info, 4, message, keyvals...)
+}
+
+func (this *Log) Info4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Info, 4, producer)
+}
+
+func (this *Log) Info4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Info, 4, producer)
+}
+
+func (this *Log) Info5(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Info, 5, message)
+}
+
+func (this *Log) Info5s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Info, 5, message)
+}
+
+func (this *Log) Info5f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Info, 5, format, args...)
+}
+
+func (this *Log) Info5fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Info, 5, format, args...)
+}
+
+func (this *Log) Info5kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Info, 5, message, keyvals...)
+}
+
+func (this *Log) Info5kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Info, 5, message, keyvals...)
+}
+
+func (this *Log) Info5l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Info, 5, producer)
+}
+
+func (this *Log) Info5ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Info, 5, producer)
+}
+
+/* -- debug messages */
+func (this *Log) Debug3(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Debug, 3, message)
+}
+
+func (this *Log) Debug3s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Debug, 3, message)
+}
+
+func (this *Log) Debug3f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Debug, 3, format, args...)
+}
+
+func (this *Log) Debug3fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Debug, 3, format, args...)
+}
+
+func (this *Log) Debug3kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Debug, 3, message, keyvals...)
+}
+
+func (this *Log) Debug3kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Debug, 3, message, keyvals...)
+}
+
+func (this *Log) Debug3l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Debug, 3, producer)
+}
+
+func (this *Log) Debug3ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Debug, 3, producer)
+}
+
+func (this *Log) Debug4(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Debug, 4, message)
+}
+
+func (this *Log) Debug4s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Debug, 4, message)
+}
+
+func (this *Log) Debug4f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Debug, 4, format, args...)
+}
+
+func (this *Log) Debug4fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Debug, 4, format, args...)
+}
+
+func (this *Log) Debug4kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Debug, 4, message, keyvals...)
+}
+
+func (this *Log) Debug4kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Debug, 4, message, keyvals...)
+}
+
+func (this *Log) Debug4l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Debug, 4, producer)
+}
+
+func (this *Log) Debug4ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Debug, 4, producer)
+}
+
+func (this *Log) Debug5(
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, this.subsystem, Debug, 5, message)
+}
+
+func (this *Log) Debug5s(
+	subsystem Subsystem,
+	message string,
+) {
+	DispatcherLogMessage(this.dispatcher, subsystem, Debug, 5, message)
+}
+
+func (this *Log) Debug5f(
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, this.subsystem, Debug, 5, format, args...)
+}
+
+func (this *Log) Debug5fs(
+	subsystem Subsystem,
+	format string,
+	args ...interface{},
+) {
+	DispatcherLogMessagef(this.dispatcher, subsystem, Debug, 5, format, args...)
+}
+
+func (this *Log) Debug5kv(
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, this.subsystem, Debug, 5, message, keyvals...)
+}
+
+func (this *Log) Debug5kvs(
+	subsystem Subsystem,
+	message string,
+	keyvals ...interface{},
+) {
+	DispatcherLogMessagekv(this.dispatcher, subsystem, Debug, 5, message, keyvals...)
+}
+
+func (this *Log) Debug5l(
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, this.subsystem, Debug, 5, producer)
+}
+
+func (this *Log) Debug5ls(
+	subsystem Subsystem,
+	producer func() string,
+) {
+	DispatcherLogMessagel(this.dispatcher, subsystem, Debug, 5, producer)
+}
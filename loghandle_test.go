@@ -0,0 +1,106 @@
+package goolog2_test
+
+import (
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+type recordingLogger struct {
+	subsystem Subsystem
+	severity  Severity
+	verbosity Verbosity
+	line      string
+	count     int
+}
+
+func (this *recordingLogger) Destroy() {
+	/* -- nothing to do */
+}
+
+func (this *recordingLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	this.subsystem = subsystem
+	this.severity = severity
+	this.verbosity = verbosity
+	this.line = object.(LineObject).GetLogLine()
+	this.count++
+}
+
+func (this *recordingLogger) Check(
+	t *testing.T,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	line string,
+) {
+	if this.subsystem != subsystem || this.severity != severity ||
+		this.verbosity != verbosity || this.line != line {
+		t.Errorf(
+			"unexpected message (%s, %s, %d, %q)",
+			this.subsystem, this.severity.Code(), this.verbosity, this.line)
+	}
+}
+
+func TestLogHandle(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	logger := &recordingLogger{}
+	dispatcher.AddLogger("recording", "", MaskAll, 5, logger)
+
+	log := NewLog(dispatcher, "library")
+	log.Error1("error")
+	logger.Check(t, "library", Error, 1, "error")
+	log.Info2f("info %d", 2)
+	logger.Check(t, "library", Info, 2, "info 2")
+	log.Warning3s("other", "warning")
+	logger.Check(t, "other", Warning, 3, "warning")
+	log.Debug5fs("other", "debug %s", "5")
+	logger.Check(t, "other", Debug, 5, "debug 5")
+	log.Info4kv("request", "user", "me")
+	logger.Check(t, "library", Info, 4, "request")
+	log.LogMessage(Critical, 1, "critical")
+	logger.Check(t, "library", Critical, 1, "critical")
+	log.LogMessagel(Warning, 2, func() string { return "lazy" })
+	logger.Check(t, "library", Warning, 2, "lazy")
+	if !log.Enabled(Debug, 5) || log.Enabled(Debug, 6) {
+		t.Errorf("unexpected enabled state")
+	}
+
+	/* -- another subsystem */
+	sub := log.WithSubsystem("library/sub")
+	sub.Error4kvs("explicit", "error")
+	logger.Check(t, "explicit", Error, 4, "error")
+	sub.Info5("info")
+	logger.Check(t, "library/sub", Info, 5, "info")
+	if sub.Dispatcher() != dispatcher || sub.Subsystem() != "library/sub" {
+		t.Errorf("unexpected handle")
+	}
+}
+
+func TestGlobalLogHandle(t *testing.T) {
+	/* -- the handle follows reinitialization of the global log */
+	log := Global()
+	for i := 0; i < 2; i++ {
+		Init("testlog")
+		logger := &recordingLogger{}
+		AddLogger("recording", "", MaskAll, 5, logger)
+		log.Info1("info")
+		logger.Check(t, "", Info, 1, "info")
+		if len(log.Dispatcher().ListLoggers()) != 1 {
+			t.Errorf("the handle should see the global loggers")
+		}
+		log.Dispatcher().Destroy()
+		if len(ListLoggers()) != 1 {
+			t.Errorf("the handle shouldn't destroy the global log")
+		}
+		Destroy()
+	}
+}
@@ -0,0 +1,212 @@
+package logreader
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time format of the Apache logs
+const accessTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+/* -- host identity user [time] "request" status length[ "referer" "agent"] */
+var accessPattern = regexp.MustCompile(
+	`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" ([0-9]{3}) ([0-9]+|-)` +
+		`(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)")?$`)
+
+// One record of the Apache common or combined format
+//
+// The values logged as "-" are empty.
+type AccessRecord struct {
+	RemoteHost string
+	Identity   string
+	User       string
+	Time       time.Time
+	Method     string
+	Resource   string
+	Protocol   string
+	Status     int
+	Length     uint64
+	Referer    string
+	Agent      string
+	// Name of the file (empty for streams)
+	File string
+	// Number of the line in the file
+	Line int
+}
+
+// Reader of the Apache common and combined formats
+//
+// Malformed lines are skipped.
+type AccessReader struct {
+	source  *lineSource
+	skipped int
+}
+
+// Create new reader of a stream
+func NewAccessReader(
+	input io.Reader,
+) *AccessReader {
+	return &AccessReader{source: newStreamSource(input)}
+}
+
+// Open an access log file
+//
+// Files with the .gz suffix are decompressed.
+//
+// Parameters:
+//     path: path of the file
+// Returns:
+//     the reader. Close it after the reading.
+func OpenAccess(
+	path string,
+) (*AccessReader, error) {
+	source, err := newFileSource([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	return &AccessReader{source: source}, nil
+}
+
+// Open an access log file and its rotated generations
+//
+// See OpenRotated.
+//
+// Parameters:
+//     path: path of the current file
+// Returns:
+//     the reader. Close it after the reading.
+func OpenAccessRotated(
+	path string,
+) (*AccessReader, error) {
+	source, err := newFileSource(rotatedPaths(path))
+	if err != nil {
+		return nil, err
+	}
+	return &AccessReader{source: source}, nil
+}
+
+// Read next record
+//
+// Returns:
+//     the record
+//     io.EOF if there are no more records
+func (this *AccessReader) Next() (*AccessRecord, error) {
+	for {
+		text, _, err := this.source.readLine()
+		if err != nil {
+			return nil, err
+		}
+		record := this.parse(text)
+		if record == nil {
+			this.skipped++
+			continue
+		}
+		return record, nil
+	}
+}
+
+// Get number of skipped malformed lines
+func (this *AccessReader) Skipped() int {
+	return this.skipped
+}
+
+// Close the opened files
+func (this *AccessReader) Close() error {
+	return this.source.close()
+}
+
+func (this *AccessReader) parse(
+	text string,
+) *AccessRecord {
+	match := accessPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	record := &AccessRecord{
+		RemoteHost: accessValue(match[1]),
+		Identity:   accessValue(match[2]),
+		User:       accessValue(match[3]),
+		Referer:    accessValue(unescapeAccessValue(match[8])),
+		Agent:      accessValue(unescapeAccessValue(match[9])),
+		File:       this.source.name,
+		Line:       this.source.line,
+	}
+	var err error
+	if record.Time, err = time.Parse(accessTimeFormat, match[4]); err != nil {
+		return nil
+	}
+	if record.Status, err = strconv.Atoi(match[6]); err != nil {
+		return nil
+	}
+	if match[7] != "-" {
+		if record.Length, err = strconv.ParseUint(match[7], 10, 64); err != nil {
+			return nil
+		}
+	}
+
+	/* -- the request line: method resource protocol */
+	request := unescapeAccessValue(match[5])
+	if request != "-" {
+		parts := strings.SplitN(request, " ", 2)
+		record.Method = parts[0]
+		if len(parts) > 1 {
+			rest := parts[1]
+			if index := strings.LastIndexByte(rest, ' '); index >= 0 {
+				record.Resource = rest[:index]
+				record.Protocol = rest[index+1:]
+			} else {
+				record.Resource = rest
+			}
+		}
+	}
+	return record
+}
+
+// Convert "-" to an empty value
+func accessValue(
+	value string,
+) string {
+	if value == "-" {
+		return ""
+	}
+	return value
+}
+
+// Revert the escaping of the Apache logs (\" \\ \n \t \xhh)
+func unescapeAccessValue(
+	value string,
+) string {
+	if strings.IndexByte(value, '\\') < 0 {
+		return value
+	}
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' || i+1 >= len(value) {
+			builder.WriteByte(c)
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'x':
+			if i+2 < len(value) {
+				if code, err := strconv.ParseUint(value[i+1:i+3], 16, 8); err == nil {
+					builder.WriteByte(byte(code))
+					i += 2
+					continue
+				}
+			}
+			builder.WriteString(`\x`)
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,115 @@
+package logreader_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Staon/goolog2"
+	. "github.com/Staon/goolog2/logreader"
+)
+
+type testAccessObject struct {
+	time  time.Time
+	agent string
+}
+
+func (this *testAccessObject) GetValues() (
+	remoteHost string,
+	identity string,
+	user string,
+	requestTime time.Time,
+	method string,
+	resource string,
+	protocol string,
+	status int,
+	length uint64,
+	referer string,
+	agent string,
+) {
+	return "10.0.0.1", "", "frank", this.time, "GET", "/index.html?a=1",
+		"HTTP/1.1", 200, 2326, "", this.agent
+}
+
+func TestAccessReaderRoundTrip(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "access.log")
+	requestTime := time.Date(2018, 8, 25, 14, 2, 3, 0, time.FixedZone("", 2*3600))
+
+	file := goolog2.NewSimpleFile(path, true)
+	common, err := goolog2.NewApacheFormatLogger(file, goolog2.ApacheFormatCommon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	combined, err := goolog2.NewApacheFormatLogger(file, goolog2.ApacheFormatCombined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Unref()
+	object := &testAccessObject{time: requestTime, agent: "Mozilla \"quoted\"\x01"}
+	common.LogObject("", "", goolog2.Info, 1, object)
+	combined.LogObject("", "", goolog2.Info, 1, object)
+	common.Destroy()
+	combined.Destroy()
+
+	reader, err := OpenAccess(path)
+	if err != nil {
+		t.Fatalf("cannot open the log: %v", err)
+	}
+	defer reader.Close()
+	var records []*AccessRecord
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("unexpected records: %d", len(records))
+	}
+	for _, record := range records {
+		if record.RemoteHost != "10.0.0.1" || record.Identity != "" ||
+			record.User != "frank" || !record.Time.Equal(requestTime) ||
+			record.Method != "GET" || record.Resource != "/index.html?a=1" ||
+			record.Protocol != "HTTP/1.1" || record.Status != 200 ||
+			record.Length != 2326 || record.Referer != "" {
+			t.Errorf("unexpected record: %+v", record)
+		}
+	}
+	if records[0].Agent != "" || records[1].Agent != object.agent || records[1].Line != 2 {
+		t.Errorf("unexpected agents: %q, %q", records[0].Agent, records[1].Agent)
+	}
+}
+
+func TestAccessReaderMalformedLines(t *testing.T) {
+	input := strings.Join([]string{
+		`127.0.0.1 - - [25/Aug/2018:14:02:03 +0000] "GET / HTTP/1.0" 304 -`,
+		`not an access record`,
+		`127.0.0.1 - - [25/Aug/2018:14:02:03 +0000] "GET / HTTP/1.0" 200 12 "unterminated`,
+		`127.0.0.1 - - [25/Aug/2018 14:02:03] "GET / HTTP/1.0" 200 12`,
+		`::1 - - [25/Aug/2018:14:02:03 +0000] "-" 400 0 "-" "-"`,
+	}, "\n")
+	reader := NewAccessReader(strings.NewReader(input))
+	first, err := reader.Next()
+	if err != nil || first.Status != 304 || first.Length != 0 || first.Resource != "/" {
+		t.Fatalf("unexpected record: %+v, %v", first, err)
+	}
+	second, err := reader.Next()
+	if err != nil || second.RemoteHost != "::1" || second.Method != "" || second.Status != 400 {
+		t.Fatalf("unexpected record: %+v, %v", second, err)
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("unexpected end: %v", err)
+	}
+	if reader.Skipped() != 3 {
+		t.Errorf("unexpected number of skipped lines: %d", reader.Skipped())
+	}
+}
@@ -0,0 +1,206 @@
+package logreader
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Staon/goolog2"
+)
+
+// Time format of the default line formatter
+const timeFormat = "2006-01-02T15:04:05"
+
+/* -- [system time ][severity, verbosity] (subsystem): message */
+var recordPattern = regexp.MustCompile(
+	`^(?:(.*?) ([0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}) )?` +
+		`\[ *([A-Z]+), ([0-9]+)\] \((.*?)\): ?(.*)$`)
+
+// One record of the default format
+type Record struct {
+	// Logging system (empty in the short format)
+	System string
+	// Time of the record (zero in the short format)
+	Time time.Time
+	// Severity of the record (zero if the code is unknown)
+	Severity goolog2.Severity
+	// Verbosity of the record
+	Verbosity goolog2.Verbosity
+	// Logging subsystem
+	Subsystem goolog2.Subsystem
+	// The message. Lines following the record which don't start
+	// a record are appended to the message separated by line breaks.
+	// The structured fields are part of the message.
+	Message string
+	// Name of the file (empty for streams)
+	File string
+	// Number of the first line of the record in the file
+	Line int
+}
+
+// Reader of the default format of the file loggers
+//
+// Both long and short formats are recognized. The reader is tolerant:
+// a line which doesn't start a record continues the message of
+// the previous record (multi-line messages). Such lines at the beginning
+// of a file are skipped.
+type Reader struct {
+	source   *lineSource
+	location *time.Location
+	pending  *Record
+	skipped  int
+}
+
+// Create new reader of a stream
+//
+// Parameters:
+//     input: the stream
+//     location: time zone of the logged times. Nil means the local time.
+// Returns:
+//     the reader
+func NewReader(
+	input io.Reader,
+	location *time.Location,
+) *Reader {
+	return newReader(newStreamSource(input), location)
+}
+
+// Open a log file
+//
+// Files with the .gz suffix are decompressed.
+//
+// Parameters:
+//     path: path of the file
+//     location: time zone of the logged times. Nil means the local time.
+// Returns:
+//     the reader. Close it after the reading.
+func Open(
+	path string,
+	location *time.Location,
+) (*Reader, error) {
+	source, err := newFileSource([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	return newReader(source, location), nil
+}
+
+// Open a log file and its rotated generations
+//
+// The generations are read from the oldest one (file.log.N) to the current
+// file, compressed generations (file.log.N.gz) are decompressed.
+//
+// Parameters:
+//     path: path of the current file
+//     location: time zone of the logged times. Nil means the local time.
+// Returns:
+//     the reader. Close it after the reading.
+func OpenRotated(
+	path string,
+	location *time.Location,
+) (*Reader, error) {
+	source, err := newFileSource(rotatedPaths(path))
+	if err != nil {
+		return nil, err
+	}
+	return newReader(source, location), nil
+}
+
+func newReader(
+	source *lineSource,
+	location *time.Location,
+) *Reader {
+	if location == nil {
+		location = time.Local
+	}
+	return &Reader{
+		source:   source,
+		location: location,
+	}
+}
+
+// Read next record
+//
+// Returns:
+//     the record
+//     io.EOF if there are no more records
+func (this *Reader) Next() (*Record, error) {
+	for {
+		text, first, err := this.source.readLine()
+		if err == io.EOF {
+			if this.pending != nil {
+				record := this.pending
+				this.pending = nil
+				return record, nil
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := this.parse(text)
+		if record == nil {
+			/* -- continuation of a multi-line message */
+			if this.pending != nil && !first {
+				this.pending.Message += "\n" + text
+			} else {
+				this.skipped++
+			}
+			continue
+		}
+
+		previous := this.pending
+		this.pending = record
+		if previous != nil {
+			return previous, nil
+		}
+	}
+}
+
+// Get number of skipped lines
+//
+// The lines which don't belong to any record are skipped.
+func (this *Reader) Skipped() int {
+	return this.skipped
+}
+
+// Close the opened files
+func (this *Reader) Close() error {
+	return this.source.close()
+}
+
+// Parse a line starting a record
+//
+// Returns:
+//     the record or nil if the line doesn't start a record
+func (this *Reader) parse(
+	text string,
+) *Record {
+	match := recordPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	verbosity, err := strconv.ParseUint(match[4], 10, 32)
+	if err != nil {
+		return nil
+	}
+	record := &Record{
+		System:    match[1],
+		Verbosity: goolog2.Verbosity(verbosity),
+		Subsystem: goolog2.Subsystem(match[5]),
+		Message:   match[6],
+		File:      this.source.name,
+		Line:      this.source.line,
+	}
+	if match[2] != "" {
+		record.Time, err = time.ParseInLocation(timeFormat, match[2], this.location)
+		if err != nil {
+			return nil
+		}
+	}
+	/* -- unknown codes are kept as zero severity */
+	record.Severity, _ = goolog2.ParseSeverity(match[3])
+	return record
+}
@@ -0,0 +1,161 @@
+package logreader_test
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Staon/goolog2"
+	. "github.com/Staon/goolog2/logreader"
+)
+
+func tempDir(
+	t *testing.T,
+) string {
+	dir, err := ioutil.TempDir("", "logreader")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	return dir
+}
+
+func readAll(
+	t *testing.T,
+	reader *Reader,
+) []*Record {
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("reading failed: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "roundtrip.log")
+	now := time.Date(2018, 8, 25, 14, 2, 3, 0, time.UTC)
+
+	file := goolog2.NewSimpleFile(path, true)
+	dispatcher := goolog2.NewLogDispatcher("readtest")
+	dispatcher.AddLogger(
+		"file", "", goolog2.MaskAll, 5,
+		goolog2.NewFileLogger(
+			goolog2.NewMockTimeSource(now), file, goolog2.NewLineFormatterDefault(false)))
+	file.Unref()
+	goolog2.DispatcherLogMessage(dispatcher, "net", goolog2.Warning, 2, "connection lost")
+	goolog2.DispatcherLogMessage(dispatcher, "", goolog2.Critical, 1, "panic:\n  goroutine 1")
+	goolog2.DispatcherLogFields(
+		dispatcher, "db", goolog2.Debug, 5, "query",
+		goolog2.Field{Key: "rows", Value: 3})
+	dispatcher.Destroy()
+
+	reader, err := Open(path, time.UTC)
+	if err != nil {
+		t.Fatalf("cannot open the log: %v", err)
+	}
+	defer reader.Close()
+	records := readAll(t, reader)
+	if len(records) != 3 || reader.Skipped() != 0 {
+		t.Fatalf("unexpected records: %d, skipped %d", len(records), reader.Skipped())
+	}
+
+	first := records[0]
+	if first.System != "readtest" || !first.Time.Equal(now) ||
+		first.Severity != goolog2.Warning || first.Verbosity != 2 ||
+		first.Subsystem != "net" || first.Message != "connection lost" ||
+		first.File != path || first.Line != 1 {
+		t.Errorf("unexpected record: %+v", first)
+	}
+	if records[1].Severity != goolog2.Critical || records[1].Subsystem != "" ||
+		records[1].Message != "panic:\n  goroutine 1" {
+		t.Errorf("unexpected multi-line record: %+v", records[1])
+	}
+	if records[2].Line != 4 || !strings.HasPrefix(records[2].Message, "query") ||
+		!strings.Contains(records[2].Message, "rows") {
+		t.Errorf("unexpected record with fields: %+v", records[2])
+	}
+}
+
+func TestReaderMalformedLines(t *testing.T) {
+	input := strings.Join([]string{
+		"garbage before the first record",
+		"[    INFO, 1] (app): short format",
+		"\tcontinuation",
+		"app 2018-08-25T14:02:03 [ UNKNOWN, 3] (): unknown severity\r",
+		"app 2018-08-25T99:02:03 [    INFO, 1] (app): invalid time",
+		"",
+	}, "\n")
+	reader := NewReader(strings.NewReader(input), time.UTC)
+	records := readAll(t, reader)
+	if len(records) != 2 || reader.Skipped() != 1 {
+		t.Fatalf("unexpected records: %d, skipped %d", len(records), reader.Skipped())
+	}
+	if records[0].System != "" || !records[0].Time.IsZero() ||
+		records[0].Severity != goolog2.Info || records[0].Line != 2 ||
+		records[0].Message != "short format\n\tcontinuation" {
+		t.Errorf("unexpected short record: %+v", records[0])
+	}
+	if records[1].Severity != 0 || records[1].Verbosity != 3 ||
+		records[1].Message != "unknown severity\n"+
+			"app 2018-08-25T99:02:03 [    INFO, 1] (app): invalid time" {
+		t.Errorf("unexpected record: %+v", records[1])
+	}
+}
+
+func TestReaderRotatedFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rotated.log")
+	writeFile := func(name string, content string) {
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(path+".2", "[    INFO, 1] (): oldest\n")
+	compressed, err := os.Create(path + ".1.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressor := gzip.NewWriter(compressed)
+	io.WriteString(compressor, "[    INFO, 1] (): compressed\nend of the record\n")
+	compressor.Close()
+	compressed.Close()
+	writeFile(path, "continuation of the previous file\n[    INFO, 1] (): current\n")
+	/* -- a gap in the generations stops the searching */
+	writeFile(path+".4", "[    INFO, 1] (): ignored\n")
+
+	reader, err := OpenRotated(path, nil)
+	if err != nil {
+		t.Fatalf("cannot open the logs: %v", err)
+	}
+	defer reader.Close()
+	records := readAll(t, reader)
+	expected := []string{"oldest", "compressed\nend of the record", "current"}
+	if len(records) != len(expected) || reader.Skipped() != 1 {
+		t.Fatalf("unexpected records: %d, skipped %d", len(records), reader.Skipped())
+	}
+	for i, record := range records {
+		if record.Message != expected[i] {
+			t.Errorf("unexpected message of the record %d: %q", i, record.Message)
+		}
+	}
+	if records[1].File != path+".1.gz" || records[2].File != path || records[2].Line != 2 {
+		t.Errorf("unexpected locations: %+v, %+v", records[1], records[2])
+	}
+
+	if _, err := OpenRotated(filepath.Join(dir, "missing.log"), nil); err == nil {
+		t.Errorf("missing file is opened")
+	}
+}
@@ -0,0 +1,160 @@
+// Package logreader reads goolog2 log files back into records.
+//
+// The Reader parses the default format of the file loggers (see
+// goolog2.NewLineFormatterDefault), the AccessReader parses the Apache
+// common and combined formats (see goolog2.NewApacheLogger). Both readers
+// stream the files line by line. The rotated generations of the files
+// (file.log.2, file.log.1.gz...) can be read in the chronological order.
+package logreader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Suffix of the compressed files
+const compressedSuffix = ".gz"
+
+// Sequence of lines read from one or more files
+type lineSource struct {
+	/* -- files not opened yet */
+	paths   []string
+	input   *bufio.Reader
+	closers []io.Closer
+	/* -- name of the current input and number of the last read line */
+	name string
+	line int
+}
+
+func newStreamSource(
+	input io.Reader,
+) *lineSource {
+	return &lineSource{
+		input: bufio.NewReader(input),
+	}
+}
+
+func newFileSource(
+	paths []string,
+) (*lineSource, error) {
+	source := &lineSource{paths: paths}
+	if err := source.openNext(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// Open the next file
+func (this *lineSource) openNext() error {
+	this.closeCurrent()
+	path := this.paths[0]
+	this.paths = this.paths[1:]
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	this.closers = append(this.closers, file)
+	var input io.Reader = file
+	if strings.HasSuffix(path, compressedSuffix) {
+		decompressor, err := gzip.NewReader(file)
+		if err != nil {
+			this.closeCurrent()
+			return err
+		}
+		this.closers = append(this.closers, decompressor)
+		input = decompressor
+	}
+	this.input = bufio.NewReader(input)
+	this.name = path
+	this.line = 0
+	return nil
+}
+
+func (this *lineSource) closeCurrent() error {
+	var err error
+	for i := len(this.closers) - 1; i >= 0; i-- {
+		if closeErr := this.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	this.closers = nil
+	this.input = nil
+	return err
+}
+
+// Read next line
+//
+// Returns:
+//     the line without the line break
+//     true if the line is the first line of a file
+//     io.EOF at the end of the last file
+func (this *lineSource) readLine() (string, bool, error) {
+	for {
+		if this.input == nil {
+			if len(this.paths) == 0 {
+				return "", false, io.EOF
+			}
+			if err := this.openNext(); err != nil {
+				return "", false, err
+			}
+		}
+
+		text, err := this.input.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", false, err
+		}
+		if text == "" && err == io.EOF {
+			/* -- end of the file, continue by the next one */
+			if closeErr := this.closeCurrent(); closeErr != nil {
+				return "", false, closeErr
+			}
+			continue
+		}
+		this.line++
+		text = strings.TrimSuffix(text, "\n")
+		text = strings.TrimSuffix(text, "\r")
+		return text, this.line == 1, nil
+	}
+}
+
+// Close the opened files
+func (this *lineSource) close() error {
+	this.paths = nil
+	return this.closeCurrent()
+}
+
+// Get paths of a log file and its rotated generations
+//
+// The generations are searched from 1 until a generation is missing.
+// A generation can be compressed (file.log.1.gz).
+//
+// Returns:
+//     the paths ordered from the oldest generation to the current file
+func rotatedPaths(
+	path string,
+) []string {
+	var generations []string
+	for generation := 1; ; generation++ {
+		name := path + "." + strconv.Itoa(generation)
+		if _, err := os.Stat(name); err == nil {
+			generations = append(generations, name)
+			continue
+		}
+		if _, err := os.Stat(name + compressedSuffix); err == nil {
+			generations = append(generations, name+compressedSuffix)
+			continue
+		}
+		break
+	}
+
+	paths := make([]string, 0, len(generations)+1)
+	for i := len(generations) - 1; i >= 0; i-- {
+		paths = append(paths, generations[i])
+	}
+	return append(paths, path)
+}
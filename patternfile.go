@@ -2,7 +2,12 @@ package goolog2
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -11,11 +16,23 @@ import (
 
 const checkInterval int64 = 30
 
+// Options of the pattern file
+type PatternFileOptions struct {
+	// Retention of the files generated from the pattern. The age of a file
+	// is determined by the time encoded in its name.
+	Retention RetentionPolicy
+}
+
 type patternFile struct {
+	errorSink
+	rotatorLinks
 	pattern    string
 	sync       bool
+	retention  RetentionPolicy
+	matcher    *patternMatcher
 	currName   string
 	currWriter FileWriter
+	tracker    errorTrackingWriter
 	lineMutex  sync.Mutex
 	refcount   int32
 }
@@ -46,27 +63,128 @@ func NewPatternFile(
 	pattern string,
 	sync bool,
 ) RotatableFileHolder {
-	holder := patternFile{
-		pattern:  pattern,
-		sync:     sync,
-		refcount: 1,
+	return NewPatternFileWithOptions(
+		timesrc, pattern, sync, PatternFileOptions{})
+}
+
+// Create new pattern file holder and report the opening error
+//
+// See NewPatternFile for description of the pattern.
+//
+// Parameters:
+//     timesrc: time source
+//     pattern: the filename pattern
+//     sync: flush the file after every line
+// Returns:
+//     the new file holder or nil if the current file cannot be opened
+//     the error
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewPatternFileE(
+	timesrc TimeSource,
+	pattern string,
+	sync bool,
+) (RotatableFileHolder, error) {
+	return NewPatternFileWithOptionsE(
+		timesrc, pattern, sync, PatternFileOptions{})
+}
+
+// Create new pattern file holder with options
+//
+// See NewPatternFile for description of the pattern.
+//
+// Parameters:
+//     timesrc: time source
+//     pattern: the filename pattern
+//     sync: flush the file after every line
+//     options: options of the holder
+// Returns:
+//     the new file holder
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewPatternFileWithOptions(
+	timesrc TimeSource,
+	pattern string,
+	sync bool,
+	options PatternFileOptions,
+) RotatableFileHolder {
+	// If the file cannot be opened, the logging just simply doesn't work.
+	// The error is reported when an error handler is set.
+	holder, err := newPatternFile(timesrc, pattern, sync, options)
+	holder.reportError(err)
+	return holder
+}
+
+// Create new pattern file holder with options and report the opening error
+//
+// See NewPatternFile for description of the pattern.
+//
+// Parameters:
+//     timesrc: time source
+//     pattern: the filename pattern
+//     sync: flush the file after every line
+//     options: options of the holder
+// Returns:
+//     the new file holder or nil if the current file cannot be opened
+//     the error
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewPatternFileWithOptionsE(
+	timesrc TimeSource,
+	pattern string,
+	sync bool,
+	options PatternFileOptions,
+) (RotatableFileHolder, error) {
+	holder, err := newPatternFile(timesrc, pattern, sync, options)
+	if err != nil {
+		holder.Unref()
+		return nil, err
+	}
+	return holder, nil
+}
+
+func newPatternFile(
+	timesrc TimeSource,
+	pattern string,
+	sync bool,
+	options PatternFileOptions,
+) (*patternFile, error) {
+	holder := &patternFile{
+		pattern:   pattern,
+		sync:      sync,
+		retention: options.Retention,
+		refcount:  1,
+	}
+	if holder.retention.isEnabled() {
+		holder.matcher = newPatternMatcher(filepath.Clean(pattern))
 	}
-	holder.Rotate(timesrc)
-	return &holder
+	err := holder.switchFile(timesrc)
+	registerReopenableFile(holder)
+	return holder, err
 }
 
 func (this *patternFile) AccessWriter(
 	functor func(writer FileWriter),
 ) {
 	/* -- log the line */
-	this.lineMutex.Lock()
-	defer this.lineMutex.Unlock()
-	if this.currWriter != nil {
-		functor(this.currWriter)
+	/* -- the deferred unlock keeps the holder usable if the functor panics */
+	err := func() error {
+		this.lineMutex.Lock()
+		defer this.lineMutex.Unlock()
+		if this.currWriter == nil {
+			return nil
+		}
+		this.tracker.reset(this.currWriter)
+		defer this.tracker.reset(nil)
+		functor(&this.tracker)
 		if this.sync {
 			this.currWriter.Sync()
 		}
-	}
+		return this.tracker.err
+	}()
+
+	/* -- the handler is invoked out of the lock, it may log */
+	this.reportError(err)
 }
 
 func (this *patternFile) Ref() FileHolder {
@@ -76,12 +194,47 @@ func (this *patternFile) Ref() FileHolder {
 
 func (this *patternFile) Unref() {
 	refcount := atomic.AddInt32(&this.refcount, -1)
-	if refcount == 0 && this.currWriter != nil {
-		this.currWriter.Close()
-		this.currWriter = nil
+	if refcount == 0 {
+		unregisterReopenableFile(this)
+		/* -- no rotator touches the file after the unlinking */
+		this.unlinkRotatorStarters(this)
+		this.lineMutex.Lock()
+		defer this.lineMutex.Unlock()
+		if this.currWriter != nil {
+			this.currWriter.Close()
+			this.currWriter = nil
+		}
 	}
 }
 
+func (this *patternFile) Reopen() error {
+	err := this.reopen()
+	this.reportError(err)
+	return err
+}
+
+func (this *patternFile) reopen() error {
+	this.lineMutex.Lock()
+	defer this.lineMutex.Unlock()
+	if this.currWriter == nil {
+		return nil
+	}
+	this.currWriter.Close()
+	file, err := os.OpenFile(
+		this.currName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file = nil
+	}
+	this.currWriter = newSimpleFileWriter(file, true)
+	return err
+}
+
+func (this *patternFile) PathChanged() bool {
+	this.lineMutex.Lock()
+	defer this.lineMutex.Unlock()
+	return writerPathChanged(this.currWriter, this.currName)
+}
+
 // See LogRotator interface
 func (this *patternFile) NeedRotate(
 	timesrc TimeSource,
@@ -93,16 +246,24 @@ func (this *patternFile) NeedRotate(
 func (this *patternFile) Rotate(
 	timesrc TimeSource,
 ) {
+	this.reportError(this.switchFile(timesrc))
+}
+
+// Switch the file if the current time generates another name
+func (this *patternFile) switchFile(
+	timesrc TimeSource,
+) error {
+	/* -- the holder has been already destroyed */
+	if atomic.LoadInt32(&this.refcount) <= 0 {
+		return nil
+	}
+
 	/* -- generate new filename */
 	newName := this.generateFilename(timesrc.Now())
 
 	/* -- if the filename differs switch the files */
+	/* -- only this goroutine writes the name, it can be read without the lock */
 	if newName != this.currName {
-
-		/* -- No one other reads this value. So I can store it
-		without any synchronization */
-		this.currName = newName
-
 		/* -- open the new file */
 		newFile, err := os.OpenFile(
 			newName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
@@ -112,15 +273,67 @@ func (this *patternFile) Rotate(
 
 		/* -- switch the file */
 		this.lineMutex.Lock()
+		if atomic.LoadInt32(&this.refcount) <= 0 {
+			this.lineMutex.Unlock()
+			if newFile != nil {
+				newFile.Close()
+			}
+			return nil
+		}
 		oldWriter := this.currWriter
 		this.currWriter = newSimpleFileWriter(newFile, true)
+		this.currName = newName
 		this.lineMutex.Unlock()
 
 		/* -- close the old file */
 		if oldWriter != nil {
 			oldWriter.Close()
 		}
+
+		if this.retention.isEnabled() {
+			this.applyRetention(timesrc)
+		}
+		return err
 	}
+	return nil
+}
+
+// Remove files generated from the pattern exceeding the retention limits
+func (this *patternFile) applyRetention(
+	timesrc TimeSource,
+) {
+	now := timesrc.Now()
+	current := filepath.Clean(this.currName)
+	var activeSize int64
+	var files []retainedFile
+	for _, path := range this.matcher.listCandidates() {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if path == current {
+			activeSize = info.Size()
+			continue
+		}
+		fileTime, matches := this.matcher.match(path, now.Location())
+		if matches {
+			files = append(files, retainedFile{
+				paths: []string{path},
+				time:  fileTime,
+				size:  info.Size(),
+			})
+		}
+	}
+
+	/* -- from the newest to the oldest */
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].time.Equal(files[j].time) {
+			return files[i].paths[0] > files[j].paths[0]
+		}
+		return files[i].time.After(files[j].time)
+	})
+	removeRetainedFiles(
+		this.retention.selectExpired(now, activeSize, files), &this.errorSink)
 }
 
 func (this *patternFile) generateFilename(
@@ -175,3 +388,111 @@ func (this *patternFile) GetNextCheckTime(
 	nextCheck := now.Add(time.Duration(checkInterval) * time.Second)
 	return nextCheck
 }
+
+// Matcher of file names generated from a pattern
+type patternMatcher struct {
+	pattern string
+	regexp  *regexp.Regexp
+	fields  []byte
+}
+
+func newPatternMatcher(
+	pattern string,
+) *patternMatcher {
+	type stateCode int
+	const (
+		INIT stateCode = iota
+		FMT
+	)
+
+	/* -- the same parsing as the generateFilename() method */
+	matcher := &patternMatcher{
+		pattern: pattern,
+	}
+	builder := &strings.Builder{}
+	builder.WriteByte('^')
+	state := INIT
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch state {
+		case INIT:
+			if c == '%' {
+				state = FMT
+			} else {
+				builder.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		case FMT:
+			switch c {
+			case 'Y':
+				builder.WriteString(`(\d{4})`)
+				matcher.fields = append(matcher.fields, c)
+			case 'm', 'd', 'H', 'M':
+				builder.WriteString(`(\d{2})`)
+				matcher.fields = append(matcher.fields, c)
+			default:
+				builder.WriteString(regexp.QuoteMeta(string(c)))
+			}
+			state = INIT
+		}
+	}
+	builder.WriteByte('$')
+	matcher.regexp = regexp.MustCompile(builder.String())
+	return matcher
+}
+
+// Check whether the path has been generated from the pattern
+//
+// Returns:
+//     time encoded in the path
+//     true if the path matches
+func (this *patternMatcher) match(
+	path string,
+	location *time.Location,
+) (time.Time, bool) {
+	groups := this.regexp.FindStringSubmatch(path)
+	if groups == nil {
+		return time.Time{}, false
+	}
+	year, month, day, hour, minute := 0, 1, 1, 0, 0
+	for i, field := range this.fields {
+		value, _ := strconv.Atoi(groups[i+1])
+		switch field {
+		case 'Y':
+			year = value
+		case 'm':
+			month = value
+		case 'd':
+			day = value
+		case 'H':
+			hour = value
+		case 'M':
+			minute = value
+		}
+	}
+	return time.Date(
+		year, time.Month(month), day, hour, minute, 0, 0, location), true
+}
+
+// List files which could be generated from the pattern
+func (this *patternMatcher) listCandidates() []string {
+	var paths []string
+	dir := filepath.Dir(this.pattern)
+	if !strings.Contains(dir, "%") {
+		/* -- all files are in one directory */
+		infos, _ := ioutil.ReadDir(dir)
+		for _, info := range infos {
+			paths = append(paths, filepath.Join(dir, info.Name()))
+		}
+		return paths
+	}
+
+	/* -- the directories are generated too */
+	root := filepath.Dir(this.pattern[:strings.Index(this.pattern, "%")])
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
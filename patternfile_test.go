@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,9 +14,7 @@ import (
 
 func TestPatternFile(t *testing.T) {
 	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
-	timesrc := &mockTimeSource{
-		now: now,
-	}
+	timesrc := NewMockTimeSource(now)
 
 	os.Remove("pattern2018-08-25-14:02.log")
 	os.Remove("pattern2018-08-25-14:03.log")
@@ -29,12 +29,14 @@ func TestPatternFile(t *testing.T) {
 	Error1("First error")
 
 	/* -- move time after the checking interval */
-	timesrc.SetTime("2018-08-25T14:02:30")
+	now, _ = time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:30")
+	timesrc.Set(now)
 	Info1("Second message")
 	Error1("Second error")
 
 	/* -- move time to force rotation of the logs */
-	timesrc.SetTime("2018-08-25T14:03:00")
+	now, _ = time.Parse("2006-01-02T15:04:05", "2018-08-25T14:03:00")
+	timesrc.Set(now)
 	Info1("Third message")
 	Error1("Third error")
 
@@ -52,3 +54,107 @@ func TestPatternFile(t *testing.T) {
 		t.Errorf("second generated log file is different!")
 	}
 }
+
+func TestPatternFileRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	/* -- files of previous days and an unrelated file */
+	days := []string{"2018-08-20", "2018-08-21", "2018-08-22", "2018-08-23", "2018-08-24"}
+	for _, day := range days {
+		ioutil.WriteFile(filepath.Join(dir, "app-"+day+".log"), []byte("line\n"), 0644)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "other.log"), []byte("line\n"), 0644)
+
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+
+	/* -- the age limit */
+	holder := NewPatternFileWithOptions(
+		timesrc, filepath.Join(dir, "app-%Y-%m-%d.log"), false,
+		PatternFileOptions{
+			Retention: RetentionPolicy{MaxAge: 96 * time.Hour},
+		})
+	holder.Unref()
+	checkPatternFiles(t, dir,
+		"app-2018-08-22.log", "app-2018-08-23.log", "app-2018-08-24.log",
+		"app-2018-08-25.log", "other.log")
+
+	/* -- the generation limit */
+	timesrc.Advance(24 * time.Hour)
+	holder = NewPatternFileWithOptions(
+		timesrc, filepath.Join(dir, "app-%Y-%m-%d.log"), false,
+		PatternFileOptions{
+			Retention: RetentionPolicy{MaxGenerations: 2},
+		})
+	holder.Unref()
+	checkPatternFiles(t, dir,
+		"app-2018-08-24.log", "app-2018-08-25.log", "app-2018-08-26.log",
+		"other.log")
+}
+
+func TestPatternFileRetentionDirectories(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, month := range []string{"2018-06", "2018-07"} {
+		os.MkdirAll(filepath.Join(dir, month), 0755)
+		ioutil.WriteFile(filepath.Join(dir, month, "app.log"), []byte("line\n"), 0644)
+	}
+	os.MkdirAll(filepath.Join(dir, "2018-08"), 0755)
+
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	holder := NewPatternFileWithOptions(
+		NewMockTimeSource(now), filepath.Join(dir, "%Y-%m", "app.log"), false,
+		PatternFileOptions{
+			Retention: RetentionPolicy{MaxGenerations: 1},
+		})
+	holder.Unref()
+	checkPatternFiles(t, dir, "2018-07/app.log", "2018-08/app.log")
+}
+
+func checkPatternFiles(t *testing.T, dir string, expected ...string) {
+	var current []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			relative, _ := filepath.Rel(dir, path)
+			current = append(current, filepath.ToSlash(relative))
+		}
+		return nil
+	})
+	if strings.Join(current, " ") != strings.Join(expected, " ") {
+		t.Errorf("unexpected files: %v", current)
+	}
+}
+
+func TestPatternFileConcurrentReopen(t *testing.T) {
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	holder := NewPatternFile(timesrc, dir+"/pattern%H:%M.log", false)
+	defer holder.Unref()
+
+	/* -- reopening runs concurrently with the rotation (see go test -race) */
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			ReopenFiles()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		timesrc.Set(now.Add(time.Duration(i) * time.Minute))
+		holder.Rotate(timesrc)
+	}
+	<-done
+}
@@ -0,0 +1,254 @@
+package goolog2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+)
+
+/* -- configuration of the global log (nil if it isn't made from a config) */
+var globalConfig *Config
+var reloadMutex sync.Mutex
+
+var errNotInitialized = errors.New("the global log isn't initialized")
+
+// Remember the configuration of the global log
+//
+// Parameters:
+//     config: a copy of the configuration (see clone) or nil
+func setGlobalConfig(
+	config *Config,
+) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	globalConfig = config
+}
+
+// Make a deep copy, the caller can change the original
+func (this *Config) clone() (*Config, error) {
+	if this == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(this)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Check whether two configurations create the same logger
+//
+// The filter parameters (subsystem, severities, verbosity and overrides)
+// are ignored, they can be changed without recreating of the logger.
+func (this *LoggerConfig) sameLogger(
+	other *LoggerConfig,
+) bool {
+	a, b := *this, *other
+	a.Subsystem, b.Subsystem = "", ""
+	a.Severities, b.Severities = "", ""
+	a.Verbosity, b.Verbosity = 0, 0
+	a.Overrides, b.Overrides = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// Reload configuration of the global log
+//
+// The new configuration is compared with the running one:
+//   - loggers with unchanged parameters keep their opened files, their
+//     subsystem, severities and verbosity are updated in place,
+//   - loggers with changed parameters are recreated,
+//   - loggers missing in the new configuration are destroyed,
+//   - new loggers are added.
+// All changes are made atomically with respect to concurrent logging.
+// Loggers added by the code (not by the configuration) aren't touched,
+// a configured logger with the same name as such a logger is an error.
+//
+// If the configuration is invalid or a new file cannot be opened,
+// the running loggers aren't changed.
+//
+// Parameters:
+//     config: the new configuration
+// Returns:
+//     an error naming the offending logger
+func ReloadConfig(
+	config *Config,
+) error {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	if globalLog == nil {
+		return errNotInitialized
+	}
+	setups, err := config.resolve()
+	if err != nil {
+		return err
+	}
+	snapshot, err := config.clone()
+	if err != nil {
+		return err
+	}
+	running := make(map[string]*LoggerConfig)
+	if globalConfig != nil {
+		if config.System != globalConfig.System {
+			return fmt.Errorf(
+				"the system identifier cannot be changed from %q to %q",
+				globalConfig.System, config.System)
+		}
+		for i := range globalConfig.Loggers {
+			running[globalConfig.Loggers[i].Name] = &globalConfig.Loggers[i]
+		}
+	}
+
+	/* -- the loggers added by the code cannot be replaced */
+	for _, info := range globalLog.ListLoggers() {
+		if _, configured := running[info.Name]; !configured && config.findLogger(info.Name) != nil {
+			return fmt.Errorf("logger %q: the name is used by a logger added by the code", info.Name)
+		}
+	}
+
+	/* -- create the new and changed loggers before anything is changed */
+	type createdLogger struct {
+		setup    *loggerSetup
+		logger   Logger
+		rotators []LogRotator
+	}
+	var created []createdLogger
+	var updated []*loggerSetup
+	for _, setup := range setups {
+		old, exists := running[setup.config.Name]
+		if exists && old.sameLogger(setup.config) {
+			updated = append(updated, setup)
+			continue
+		}
+		logger, rotators, err := setup.newLogger(timeSource)
+		if err != nil {
+			for _, item := range created {
+				item.logger.Destroy()
+			}
+			return fmt.Errorf("logger %q: %s", setup.config.Name, err)
+		}
+		created = append(created, createdLogger{setup, logger, rotators})
+	}
+
+	/* -- switch the loggers atomically */
+	globalLog.Reconfigure(func(loggers LoggerSet) {
+		for name := range running {
+			if config.findLogger(name) == nil {
+				loggers.RemoveLogger(name)
+			}
+		}
+		for _, setup := range updated {
+			name := setup.config.Name
+			loggers.SetLoggerSubsystem(name, Subsystem(setup.config.Subsystem))
+			loggers.SetLoggerSeverities(name, setup.severities)
+			loggers.SetLoggerVerbosity(name, Verbosity(setup.config.Verbosity))
+			for _, override := range running[name].Overrides {
+				loggers.ClearLoggerSubsystemVerbosity(name, Subsystem(override.Subsystem))
+			}
+			setup.setOverrides(loggers)
+		}
+		for _, item := range created {
+			item.setup.addLogger(loggers, item.logger)
+		}
+	})
+	for _, item := range created {
+		for _, rotator := range item.rotators {
+			AddLogRotator(rotator)
+		}
+	}
+
+	globalConfig = snapshot
+	return nil
+}
+
+// Reload configuration of the global log from a file
+//
+// See ReloadConfig.
+//
+// Parameters:
+//     path: path of the JSON configuration file
+// Returns:
+//     an error naming the offending logger
+func ReloadConfigFile(
+	path string,
+) error {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return ReloadConfig(config)
+}
+
+func (this *Config) findLogger(
+	name string,
+) *LoggerConfig {
+	for i := range this.Loggers {
+		if this.Loggers[i].Name == name {
+			return &this.Loggers[i]
+		}
+	}
+	return nil
+}
+
+// Reload the configuration file when a signal is received
+//
+// A failure of the reloading is logged into the running loggers.
+//
+// Parameters:
+//     path: path of the JSON configuration file
+//     signals: the signals. If no signal is passed, SIGUSR1 is used
+//         (SIGHUP is the default signal of HandleReopenSignal). There is
+//         no default signal on Windows and Plan 9, nothing is handled
+//         if no signal is passed there.
+// Returns:
+//     a function stopping the handling of the signals. Invoke it before
+//     the global log is destroyed.
+func HandleReloadSignal(
+	path string,
+	signals ...os.Signal,
+) func() {
+	if len(signals) == 0 {
+		signals = defaultReloadSignals
+	}
+	if len(signals) == 0 {
+		/* -- signal.Notify without signals would catch all of them */
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case <-ch:
+				err := ReloadConfigFile(path)
+				if err != nil && err != errNotInitialized {
+					LogMessagef(
+						"", Error, 1,
+						"reloading of the logging configuration failed: %s", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			<-finished
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package goolog2_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+func reloadTestConfig(
+	dir string,
+	verbosity int,
+	loggers ...string,
+) *Config {
+	config := &Config{System: "testlog"}
+	for _, name := range loggers {
+		config.Loggers = append(config.Loggers, LoggerConfig{
+			Name:      name,
+			Type:      "file",
+			Verbosity: verbosity,
+			Path:      filepath.Join(dir, name+".log"),
+		})
+	}
+	return config
+}
+
+func readReloadLog(
+	dir string,
+	name string,
+) string {
+	content, _ := ioutil.ReadFile(filepath.Join(dir, name))
+	return string(content)
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := InitWithConfig(reloadTestConfig(dir, 1, "main", "extra")); err != nil {
+		t.Fatal(err)
+	}
+	defer Destroy()
+
+	Info2("invisible")
+	/* -- the kept logger continues in the renamed file */
+	os.Rename(filepath.Join(dir, "main.log"), filepath.Join(dir, "main.log.old"))
+	if err := ReloadConfig(reloadTestConfig(dir, 2, "main", "new")); err != nil {
+		t.Fatal(err)
+	}
+	Info2("visible")
+
+	if content := readReloadLog(dir, "main.log.old"); !strings.HasSuffix(content, "visible\n") ||
+		strings.Contains(content, "invisible") {
+		t.Errorf("unexpected content of the kept logger: %q", content)
+	}
+	if content := readReloadLog(dir, "extra.log"); content != "" {
+		t.Errorf("the removed logger has logged: %q", content)
+	}
+	if content := readReloadLog(dir, "new.log"); !strings.HasSuffix(content, "visible\n") {
+		t.Errorf("unexpected content of the new logger: %q", content)
+	}
+	loggers := ListLoggers()
+	if len(loggers) != 2 || loggers[0].Name != "main" || loggers[0].Verbosity != 2 ||
+		loggers[1].Name != "new" {
+		t.Errorf("unexpected loggers: %v", loggers)
+	}
+
+	/* -- the overrides are changed in place */
+	config := reloadTestConfig(dir, 2, "main", "new")
+	config.Loggers[0].Overrides = []OverrideConfig{{Subsystem: "db", Verbosity: 4}}
+	if err := ReloadConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	Info4s("db/pool", "db override")
+	if content := readReloadLog(dir, "main.log.old"); !strings.HasSuffix(content, "db override\n") {
+		t.Errorf("the override isn't applied: %q", content)
+	}
+	if err := ReloadConfig(reloadTestConfig(dir, 2, "main", "new")); err != nil {
+		t.Fatal(err)
+	}
+	Info4s("db/pool", "db cleared")
+	if content := readReloadLog(dir, "main.log.old"); strings.Contains(content, "db cleared") {
+		t.Errorf("the override isn't cleared: %q", content)
+	}
+
+	/* -- a changed path recreates the logger */
+	config = reloadTestConfig(dir, 2, "main", "new")
+	config.Loggers[0].Path = filepath.Join(dir, "moved.log")
+	if err := ReloadConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	Info1("moved")
+	if content := readReloadLog(dir, "moved.log"); !strings.HasSuffix(content, "moved\n") {
+		t.Errorf("unexpected content of the recreated logger: %q", content)
+	}
+
+	/* -- a failed reload doesn't change anything */
+	config = reloadTestConfig(dir, 5, "main", "broken")
+	config.Loggers[1].Path = filepath.Join(dir, "missing", "broken.log")
+	err = ReloadConfig(config)
+	if err == nil || !strings.HasPrefix(err.Error(), `logger "broken": `) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if loggers := ListLoggers(); len(loggers) != 2 || loggers[0].Verbosity != 2 {
+		t.Errorf("the loggers have been changed: %v", loggers)
+	}
+}
+
+func TestReloadConfigCodeLoggers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	Init("testlog")
+	defer Destroy()
+	AddFileLogger("main", "", MaskAll, 1, filepath.Join(dir, "code.log"), false)
+
+	/* -- the logger added by the code cannot be replaced */
+	err = ReloadConfig(reloadTestConfig(dir, 3, "main", "extra"))
+	if err == nil || !strings.HasPrefix(err.Error(), `logger "main": `) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if loggers := ListLoggers(); len(loggers) != 1 || loggers[0].Verbosity != 1 {
+		t.Errorf("the loggers have been changed: %v", loggers)
+	}
+
+	if err := ReloadConfig(reloadTestConfig(dir, 3, "extra")); err != nil {
+		t.Fatal(err)
+	}
+	if loggers := ListLoggers(); len(loggers) != 2 {
+		t.Errorf("unexpected loggers: %v", loggers)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package goolog2
+
+import (
+	"os"
+)
+
+/* -- the platform hasn't any user signal, the signals must be passed */
+var defaultReloadSignals []os.Signal
@@ -0,0 +1,12 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package goolog2
+
+import (
+	"os"
+	"syscall"
+)
+
+/* -- SIGHUP is already used for reopening of the files */
+var defaultReloadSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,46 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package goolog2_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestReloadSignal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := InitWithConfig(reloadTestConfig(dir, 1, "main")); err != nil {
+		t.Fatal(err)
+	}
+	defer Destroy()
+
+	configPath := filepath.Join(dir, "log.json")
+	config := `{"system": "testlog", "loggers": [{"name": "main", "type": "file", "verbosity": 4, "path": "` +
+		filepath.Join(dir, "main.log") + `"}]}`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stop := HandleReloadSignal(configPath)
+	defer stop()
+
+	process, _ := os.FindProcess(os.Getpid())
+	process.Signal(syscall.SIGUSR1)
+	deadline := time.Now().Add(5 * time.Second)
+	for ListLoggers()[0].Verbosity != 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the configuration hasn't been reloaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,164 @@
+package goolog2
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var reopenMutex sync.Mutex
+var reopenFiles = map[ReopenableFileHolder]struct{}{}
+
+// Register an opened file holder to be reopened by ReopenFiles
+func registerReopenableFile(
+	holder ReopenableFileHolder,
+) {
+	reopenMutex.Lock()
+	defer reopenMutex.Unlock()
+	reopenFiles[holder] = struct{}{}
+}
+
+// Unregister a closed file holder
+func unregisterReopenableFile(
+	holder ReopenableFileHolder,
+) {
+	reopenMutex.Lock()
+	defer reopenMutex.Unlock()
+	delete(reopenFiles, holder)
+}
+
+// Get a snapshot of registered file holders
+func listReopenableFiles() []ReopenableFileHolder {
+	reopenMutex.Lock()
+	defer reopenMutex.Unlock()
+	holders := make([]ReopenableFileHolder, 0, len(reopenFiles))
+	for holder := range reopenFiles {
+		holders = append(holders, holder)
+	}
+	return holders
+}
+
+// Check whether the path points to another file than the writer
+//
+// A missing file is considered changed as well.
+func writerPathChanged(
+	writer FileWriter,
+	path string,
+) bool {
+	if writer == nil {
+		return false
+	}
+	opened := writer.Stat()
+	current, err := os.Stat(path)
+	if opened == nil || err != nil {
+		return true
+	}
+	return !os.SameFile(opened, current)
+}
+
+// Reopen all opened logging files
+//
+// The function is intended to be called after an external tool
+// (like logrotate) renames the logging files. The files are reopened
+// by their paths, hence following messages are written into new files.
+//
+// Returns:
+//     the first error which occurred while reopening of the files.
+//     All files are tried to be reopened despite the error.
+func ReopenFiles() error {
+	var result error
+	for _, holder := range listReopenableFiles() {
+		if err := holder.Reopen(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Reopen the logging files when a signal is received
+//
+// Parameters:
+//     signals: the signals. If no signal is passed, SIGHUP is used.
+// Returns:
+//     a function stopping the handling of the signals
+func HandleReopenSignal(
+	signals ...os.Signal,
+) func() {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case <-ch:
+				ReopenFiles()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			<-finished
+		})
+	}
+}
+
+// Rotator checking whether the logging files have been moved away
+type reopenWatcher struct {
+	interval time.Duration
+}
+
+// Watch the logging files and reopen them when they're moved away
+//
+// This is an alternative to the reopening signal: the paths of opened
+// logging files are periodically checked by the global rotator. If a path
+// doesn't point to the opened file anymore (the file has been renamed
+// or removed), the file is reopened.
+//
+// Parameters:
+//     interval: period of the checks
+func AddReopenWatcher(
+	interval time.Duration,
+) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	globalRotator.Add(&reopenWatcher{interval: interval})
+}
+
+// See LogRotator interface
+func (this *reopenWatcher) NeedRotate(
+	timesrc TimeSource,
+) bool {
+	return true
+}
+
+// See LogRotator interface
+func (this *reopenWatcher) Rotate(
+	timesrc TimeSource,
+) {
+	for _, holder := range listReopenableFiles() {
+		if holder.PathChanged() {
+			holder.Reopen()
+		}
+	}
+}
+
+// See LogRotator interface
+func (this *reopenWatcher) GetNextCheckTime(
+	timesrc TimeSource,
+) time.Time {
+	return timesrc.Now().Add(this.interval)
+}
@@ -0,0 +1,115 @@
+package goolog2_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+func reopenLogClean() {
+	os.Remove("reopen.log")
+	os.Remove("reopen.log.old")
+}
+
+func checkReopenLog(
+	t *testing.T,
+	name string,
+	expected ...string,
+) {
+	content, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("cannot read the file '%s': %s", name, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected content of the file '%s':\n%s", name, content)
+	}
+	for i := range expected {
+		if !strings.HasSuffix(lines[i], expected[i]) {
+			t.Errorf("unexpected content of the file '%s':\n%s", name, content)
+			break
+		}
+	}
+}
+
+func TestReopenFiles(t *testing.T) {
+	reopenLogClean()
+	defer reopenLogClean()
+	Init("testlog")
+	defer Destroy()
+	AddFileLogger("file", "", MaskAll, 5, "reopen.log", true)
+
+	Info1("before")
+	if err := os.Rename("reopen.log", "reopen.log.old"); err != nil {
+		t.Fatal(err)
+	}
+	Info1("renamed")
+	if err := ReopenFiles(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	Info1("reopened")
+
+	checkReopenLog(t, "reopen.log.old", "before", "renamed")
+	checkReopenLog(t, "reopen.log", "reopened")
+}
+
+func TestReopenSignal(t *testing.T) {
+	reopenLogClean()
+	defer reopenLogClean()
+	Init("testlog")
+	defer Destroy()
+	AddFileLogger("file", "", MaskAll, 5, "reopen.log", true)
+	stop := HandleReopenSignal()
+	defer stop()
+
+	Info1("before")
+	if err := os.Rename("reopen.log", "reopen.log.old"); err != nil {
+		t.Fatal(err)
+	}
+	process, _ := os.FindProcess(os.Getpid())
+	process.Signal(syscall.SIGHUP)
+
+	/* -- the signal is handled asynchronously */
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat("reopen.log"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the file hasn't been reopened")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	Info1("reopened")
+
+	checkReopenLog(t, "reopen.log.old", "before")
+	checkReopenLog(t, "reopen.log", "reopened")
+}
+
+func TestReopenWatcher(t *testing.T) {
+	reopenLogClean()
+	defer reopenLogClean()
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+	InitWithTimeSource("testlog", timesrc)
+	defer Destroy()
+	AddFileLogger("file", "", MaskAll, 5, "reopen.log", true)
+	AddReopenWatcher(time.Minute)
+
+	Info1("before")
+	if err := os.Rename("reopen.log", "reopen.log.old"); err != nil {
+		t.Fatal(err)
+	}
+	timesrc.Advance(30 * time.Second)
+	Info1("not checked")
+	timesrc.Advance(time.Minute)
+	Info1("reopened")
+
+	checkReopenLog(t, "reopen.log.old", "before", "not checked")
+	checkReopenLog(t, "reopen.log", "reopened")
+}
@@ -0,0 +1,78 @@
+package goolog2
+
+import (
+	"os"
+	"time"
+)
+
+// Retention policy of rotated log files
+//
+// The limits are applied by the rotator after every rotation. Zero value
+// of a limit disables it.
+type RetentionPolicy struct {
+	// Maximal number of kept rotated files
+	MaxGenerations int
+	// Maximal age of kept rotated files
+	MaxAge time.Duration
+	// Maximal total size of the log files including the active one
+	MaxTotalBytes int64
+}
+
+// One rotated log file (or a group of files of one generation)
+type retainedFile struct {
+	paths []string
+	time  time.Time
+	size  int64
+}
+
+// Check whether the policy limits something
+func (this *RetentionPolicy) isEnabled() bool {
+	return this.MaxGenerations > 0 || this.MaxAge > 0 || this.MaxTotalBytes > 0
+}
+
+// Select rotated files to be removed
+//
+// The first file exceeding a limit and all older files are selected.
+// Hence the kept files make always a continuous sequence.
+//
+// Parameters:
+//     now: current time
+//     activeSize: size of the active log file
+//     files: rotated files sorted from the newest to the oldest
+// Returns:
+//     the files to be removed
+func (this *RetentionPolicy) selectExpired(
+	now time.Time,
+	activeSize int64,
+	files []retainedFile,
+) []retainedFile {
+	total := activeSize
+	for i, file := range files {
+		total += file.size
+		if (this.MaxGenerations > 0 && i >= this.MaxGenerations) ||
+			(this.MaxAge > 0 && now.Sub(file.time) > this.MaxAge) ||
+			(this.MaxTotalBytes > 0 && total > this.MaxTotalBytes) {
+			return files[i:]
+		}
+	}
+	return nil
+}
+
+// Remove the selected files
+//
+// Parameters:
+//     files: the files
+//     sink: the sink receiving the errors of the removing. A file which
+//         doesn't exist anymore isn't an error.
+func removeRetainedFiles(
+	files []retainedFile,
+	sink *errorSink,
+) {
+	for _, file := range files {
+		for _, path := range file.paths {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				sink.reportError(err)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package goolog2
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 	"strconv"
 	"sync"
@@ -8,14 +10,79 @@ import (
 	"time"
 )
 
+// Compression of rotated log files
+type Compression int
+
+const (
+	// The rotated files are kept uncompressed
+	CompressNone Compression = iota
+	// The rotated files are compressed by gzip (file.log.1.gz)
+	CompressGzip
+)
+
+const compressedSuffix = ".gz"
+
+// Period of the time-based rotation
+type RotationPeriod int
+
+const (
+	// No time-based rotation
+	RotateNever RotationPeriod = iota
+	// Rotate every hour
+	RotateHourly
+	// Rotate every day
+	RotateDaily
+	// Rotate every week
+	RotateWeekly
+)
+
+// Schedule of the time-based rotation
+type RotationSchedule struct {
+	// Period of the rotation
+	Period RotationPeriod
+	// Offset of the rotation from the beginning of the period: offset
+	// in the hour for the hourly rotation, time of the day for the daily
+	// and weekly rotation
+	At time.Duration
+	// Day of the weekly rotation
+	Weekday time.Weekday
+}
+
+// Options of the rotatable file
+type RotatableFileOptions struct {
+	// Make log rotation if log size is bigger than MaxSize. Zero disables
+	// the size rotation.
+	MaxSize int64
+	// Time interval to check the log size; usually minutes or tens of minutes
+	CheckInterval time.Duration
+	// Schedule of the time-based rotation. It can be combined with
+	// the size rotation. Empty files are not rotated.
+	Schedule RotationSchedule
+	// Compression of the rotated files
+	Compression Compression
+	// Compression level (see compress/gzip). Zero means the default level.
+	CompressionLevel int
+	// Retention of the rotated files. The age of a rotated file is
+	// determined by its modification time.
+	Retention RetentionPolicy
+}
+
 type rotatableFile struct {
-	filePath      string
-	maxSize       int64
-	writer        FileWriter
-	checkInterval time.Duration
-	mutex         sync.Mutex
-	sync          bool
-	refcount      int32
+	errorSink
+	rotatorLinks
+	filePath         string
+	maxSize          int64
+	writer           FileWriter
+	tracker          errorTrackingWriter
+	checkInterval    time.Duration
+	schedule         RotationSchedule
+	nextRotation     time.Time
+	compression      Compression
+	compressionLevel int
+	retention        RetentionPolicy
+	mutex            sync.Mutex
+	sync             bool
+	refcount         int32
 }
 
 // Create new rotatable file holder.
@@ -30,35 +97,136 @@ type rotatableFile struct {
 // Note: the reference counter is set to 1. You have to invoke Unref()
 //     to clean up the holder.
 func NewRotatableFile(filePath string, sync bool, maxSize int64, checkInterval time.Duration) RotatableFileHolder {
-	holder := rotatableFile{
-		filePath:      filePath,
-		maxSize:       maxSize,
-		checkInterval: checkInterval,
-		sync:          sync,
-		refcount:      1,
-	}
-
-	// I ignore the error here - if the file cannot be opened, the logging
-	// just simply doesn't work. However, if I cannot open the logging
-	// file, I cannot report the error.
-	file, _ := os.OpenFile(
+	return NewRotatableFileWithOptions(
+		filePath,
+		sync,
+		RotatableFileOptions{
+			MaxSize:       maxSize,
+			CheckInterval: checkInterval,
+		})
+}
+
+// Create new rotatable file holder and report the opening error
+//
+// Parameters:
+//     filepath: name of the logging file
+//     sync: if true, the stream is flushed after every message
+//     maxSize:  Make log rotation if log size is bigger than maxSize.
+//     checkInterval: time interval to check the log size; usually minutes or tens of minutes
+// Returns:
+//     the new rotatable file holder or nil if the file cannot be opened
+//     the error
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewRotatableFileE(filePath string, sync bool, maxSize int64, checkInterval time.Duration) (RotatableFileHolder, error) {
+	return NewRotatableFileWithOptionsE(
+		filePath,
+		sync,
+		RotatableFileOptions{
+			MaxSize:       maxSize,
+			CheckInterval: checkInterval,
+		})
+}
+
+// Create new rotatable file holder with options.
+//
+// Parameters:
+//     filepath: name of the logging file
+//     sync: if true, the stream is flushed after every message
+//     options: options of the rotation
+// Returns:
+//     the new rotatable file holder
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewRotatableFileWithOptions(
+	filePath string,
+	sync bool,
+	options RotatableFileOptions,
+) RotatableFileHolder {
+	// If the file cannot be opened, the logging just simply doesn't work.
+	// The error is reported when an error handler is set.
+	holder, err := newRotatableFile(filePath, sync, options)
+	holder.reportError(err)
+	return holder
+}
+
+// Create new rotatable file holder with options and report the opening error
+//
+// Parameters:
+//     filepath: name of the logging file
+//     sync: if true, the stream is flushed after every message
+//     options: options of the rotation
+// Returns:
+//     the new rotatable file holder or nil if the file cannot be opened
+//     the error
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewRotatableFileWithOptionsE(
+	filePath string,
+	sync bool,
+	options RotatableFileOptions,
+) (RotatableFileHolder, error) {
+	holder, err := newRotatableFile(filePath, sync, options)
+	if err != nil {
+		holder.Unref()
+		return nil, err
+	}
+	return holder, nil
+}
+
+func newRotatableFile(
+	filePath string,
+	sync bool,
+	options RotatableFileOptions,
+) (*rotatableFile, error) {
+	compressionLevel := options.CompressionLevel
+	if compressionLevel == 0 {
+		compressionLevel = gzip.DefaultCompression
+	}
+	holder := &rotatableFile{
+		filePath:         filePath,
+		maxSize:          options.MaxSize,
+		checkInterval:    options.CheckInterval,
+		schedule:         options.Schedule,
+		compression:      options.Compression,
+		compressionLevel: compressionLevel,
+		retention:        options.Retention,
+		sync:             sync,
+		refcount:         1,
+	}
+
+	file, err := os.OpenFile(
 		filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file = nil
+	}
 	holder.writer = newSimpleFileWriter(file, true)
-	return &holder
+	registerReopenableFile(holder)
+	return holder, err
 }
 
 func (this *rotatableFile) AccessWriter(
 	functor func(writer FileWriter),
 ) {
-	if this.writer != nil {
-		/* --  The lock avoids inter-mixing of logging lines */
+	/* --  The lock avoids inter-mixing of logging lines */
+	/* -- the deferred unlock keeps the holder usable if the functor panics */
+	err := func() error {
 		this.mutex.Lock()
 		defer this.mutex.Unlock()
-		functor(this.writer)
+		if this.writer == nil {
+			return nil
+		}
+		this.tracker.reset(this.writer)
+		defer this.tracker.reset(nil)
+		functor(&this.tracker)
 		if this.sync {
 			this.writer.Sync()
 		}
-	}
+		return this.tracker.err
+	}()
+
+	/* -- the handler is invoked out of the lock, it may log */
+	this.reportError(err)
 }
 
 func (this *rotatableFile) Ref() FileHolder {
@@ -69,6 +237,11 @@ func (this *rotatableFile) Ref() FileHolder {
 func (this *rotatableFile) Unref() {
 	refcount := atomic.AddInt32(&this.refcount, -1)
 	if refcount == 0 {
+		unregisterReopenableFile(this)
+		/* -- no rotator touches the file after the unlinking */
+		this.unlinkRotatorStarters(this)
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
 		if this.writer != nil {
 			this.writer.Close()
 			this.writer = nil
@@ -76,43 +249,268 @@ func (this *rotatableFile) Unref() {
 	}
 }
 
+func (this *rotatableFile) Reopen() error {
+	err := this.reopen()
+	this.reportError(err)
+	return err
+}
+
+func (this *rotatableFile) reopen() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.writer == nil {
+		return nil
+	}
+	this.writer.Close()
+	file, err := os.OpenFile(
+		this.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file = nil
+	}
+	this.writer = newSimpleFileWriter(file, true)
+	return err
+}
+
+func (this *rotatableFile) PathChanged() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return writerPathChanged(this.writer, this.filePath)
+}
+
 // See LogRotator interface
 func (this *rotatableFile) NeedRotate(timesrc TimeSource) bool {
-	if this.maxSize == 0 {
+	/* -- the scheduled rotation */
+	scheduled := false
+	if this.schedule.Period != RotateNever && !this.nextRotation.IsZero() {
+		now := timesrc.Now()
+		if !now.Before(this.nextRotation) {
+			scheduled = true
+			this.nextRotation = this.schedule.next(now)
+		}
+	}
+	if this.maxSize == 0 && !scheduled {
+		return false
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.writer == nil {
+		/* -- the holder has been already destroyed */
 		return false
 	}
 	this.writer.Sync()
 	fileInfo := this.writer.Stat()
-	return fileInfo != nil && fileInfo.Size() > this.maxSize
+	if fileInfo == nil {
+		return false
+	}
+	return (scheduled && fileInfo.Size() > 0) ||
+		(this.maxSize > 0 && fileInfo.Size() > this.maxSize)
 }
 
 // See LogRotator interface
 func (this *rotatableFile) Rotate(timesrc TimeSource) {
 	// A error in this part is not fatal. It will be recovered in next successfull Rotate().
-	i := 0
-	var err error
-	for err == nil {
+	i := 1
+	for this.generationExists(i) {
 		i++
-		_, err = os.Stat(this.filePath + "." + strconv.Itoa(i))
 	}
 	for ; i > 1; i-- {
-		source := this.filePath + "." + strconv.Itoa(i-1)
-		target := this.filePath + "." + strconv.Itoa(i)
-		if err := os.Rename(source, target); err != nil {
+		if err := this.renameGeneration(i-1, i); err != nil {
+			this.reportError(err)
 			return
 		}
 	}
 	// rename current file
 	this.mutex.Lock() // stop writing to file
-	defer this.mutex.Unlock()
+	if this.writer == nil {
+		this.mutex.Unlock()
+		return
+	}
 	this.writer.Close()
-	os.Rename(this.filePath, this.filePath+".1")
-	file, _ := os.OpenFile(
+	renameErr := os.Rename(this.filePath, this.generationName(1))
+	file, openErr := os.OpenFile(
 		this.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if openErr != nil {
+		file = nil
+	}
 	this.writer = newSimpleFileWriter(file, true)
+	this.mutex.Unlock()
+	this.reportError(renameErr)
+	this.reportError(openErr)
+
+	// The compression runs in the rotator goroutine, the logging isn't blocked.
+	if this.compression == CompressGzip {
+		for i := 1; this.generationExists(i); i++ {
+			this.reportError(this.compressGeneration(i))
+		}
+	}
+
+	if this.retention.isEnabled() {
+		this.applyRetention(timesrc)
+	}
+}
+
+// Remove rotated generations exceeding the retention limits
+func (this *rotatableFile) applyRetention(timesrc TimeSource) {
+	var files []retainedFile
+	for i := 1; ; i++ {
+		file := retainedFile{}
+		name := this.generationName(i)
+		for _, path := range []string{name, name + compressedSuffix} {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			file.paths = append(file.paths, path)
+			file.size += info.Size()
+			if info.ModTime().After(file.time) {
+				file.time = info.ModTime()
+			}
+		}
+		if len(file.paths) == 0 {
+			break
+		}
+		files = append(files, file)
+	}
+
+	var activeSize int64
+	if info, err := os.Stat(this.filePath); err == nil {
+		activeSize = info.Size()
+	}
+	removeRetainedFiles(
+		this.retention.selectExpired(timesrc.Now(), activeSize, files),
+		&this.errorSink)
+}
+
+func (this *rotatableFile) generationName(generation int) string {
+	return this.filePath + "." + strconv.Itoa(generation)
+}
+
+// The generation exists either uncompressed or compressed
+func (this *rotatableFile) generationExists(generation int) bool {
+	name := this.generationName(generation)
+	if _, err := os.Stat(name); err == nil {
+		return true
+	}
+	_, err := os.Stat(name + compressedSuffix)
+	return err == nil
+}
+
+// Rename both forms of the generation (if they exist)
+func (this *rotatableFile) renameGeneration(source, target int) error {
+	sourceName := this.generationName(source)
+	targetName := this.generationName(target)
+	for _, suffix := range []string{"", compressedSuffix} {
+		if _, err := os.Stat(sourceName + suffix); err != nil {
+			continue
+		}
+		if err := os.Rename(sourceName+suffix, targetName+suffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compress an uncompressed generation
+//
+// The archive is written into a temporary file which replaces the final
+// name only after it has been completely written and flushed. Hence
+// a half-written archive never replaces a good one. If the process
+// crashes, the uncompressed file is still present and it's compressed
+// at the next rotation.
+func (this *rotatableFile) compressGeneration(generation int) error {
+	name := this.generationName(generation)
+	source, err := os.Open(name)
+	if err != nil {
+		/* -- the generation is already compressed */
+		return nil
+	}
+	defer source.Close()
+
+	tmpName := name + compressedSuffix + ".tmp"
+	target, err := os.OpenFile(
+		tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		defer target.Close()
+		compressor, err := gzip.NewWriterLevel(target, this.compressionLevel)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(compressor, source); err != nil {
+			return err
+		}
+		if err := compressor.Close(); err != nil {
+			return err
+		}
+		return target.Sync()
+	}()
+	if err == nil {
+		err = os.Rename(tmpName, name+compressedSuffix)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Remove(name)
 }
 
 // See LogRotator interface
 func (this *rotatableFile) GetNextCheckTime(timesrc TimeSource) time.Time {
-	return timesrc.Now().Add(this.checkInterval)
+	now := timesrc.Now()
+	var next time.Time
+	if this.checkInterval > 0 {
+		next = now.Add(this.checkInterval)
+	}
+	if this.schedule.Period != RotateNever {
+		if this.nextRotation.IsZero() {
+			this.nextRotation = this.schedule.next(now)
+		}
+		if next.IsZero() || this.nextRotation.Before(next) {
+			next = this.nextRotation
+		}
+	}
+	if next.IsZero() {
+		/* -- nothing to check, just sleep */
+		next = now.Add(time.Hour)
+	}
+	return next
+}
+
+// Get the first scheduled rotation after the specified time
+//
+// The rotation time is computed in the location of the time.
+func (this *RotationSchedule) next(after time.Time) time.Time {
+	offset := this.At
+	hour := int(offset / time.Hour)
+	minute := int(offset % time.Hour / time.Minute)
+	second := int(offset % time.Minute / time.Second)
+	year, month, day := after.Date()
+	location := after.Location()
+
+	var next time.Time
+	switch this.Period {
+	case RotateHourly:
+		next = time.Date(
+			year, month, day, after.Hour(), minute, second, 0, location)
+		if !next.After(after) {
+			next = next.Add(time.Hour)
+		}
+	case RotateDaily:
+		next = time.Date(year, month, day, hour, minute, second, 0, location)
+		if !next.After(after) {
+			next = time.Date(year, month, day+1, hour, minute, second, 0, location)
+		}
+	case RotateWeekly:
+		days := (int(this.Weekday) - int(after.Weekday()) + 7) % 7
+		next = time.Date(
+			year, month, day+days, hour, minute, second, 0, location)
+		if !next.After(after) {
+			next = time.Date(
+				year, month, day+days+7, hour, minute, second, 0, location)
+		}
+	}
+	return next
 }
@@ -1,7 +1,10 @@
 package goolog2_test
 
 import (
+	"compress/gzip"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,31 +30,31 @@ func TestRotatableFile(t *testing.T) {
 
 	/* -- Shift the time by half of checking interval. */
 	Error1("Second error")
-	timesrc.ShiftTime(time.Minute)
+	timesrc.Advance(time.Minute)
 	testRotatableLogExists(t, "rotatable", false, false, false, "1 minute")
 
 	/* -- Shift the time by more than checking interval. */
 	//    The log file is too short to rotate.
 	Error1("Third error")
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	testRotatableLogExists(t, "rotatable", false, false, false, "4 minutes")
 
 	/* -- Shift the time by more than checking interval */
 	//    The log file is long enough to rotate.
 	Error1("First " + long_message)
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	testRotatableLogExists(t, "rotatable", true, false, false, "7 minutes")
 
 	/* -- Shift the time by half of checking interval. */
 	//    The log file is long enough to rotate but the time to check file it not yet.
 	Error1("Second " + long_message)
-	timesrc.ShiftTime(time.Minute)
+	timesrc.Advance(time.Minute)
 	testRotatableLogExists(t, "rotatable", true, false, false, "8 minutes")
 
 	/* -- Shift the time by checking interval. */
 	//    The log file is long enough to rotate.
 	Error1("Short message")
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	testRotatableLogExists(t, "rotatable", true, true, false, "11 minutes")
 }
 
@@ -76,12 +79,12 @@ func TestRotatableRecovery(t *testing.T) {
 	/* -- Create log.1 --*/
 	Error1("First " + long_message)
 	Error1("First short error")
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	testRotatableLogExists(t, "rotatable", true, true, false, "3 minutes")
 	/* -- Now is the sequence without holes. It works as usual. --*/
 	Error1("Second " + long_message)
 	Error1("Second short error")
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	testRotatableLogExists(t, "rotatable", true, true, true, "6 minutes")
 }
 
@@ -111,39 +114,37 @@ func TestMoreRotatableLogs(t *testing.T) {
 	/* -- Shift the time by 3 minutes - the time to the rotate: */
 	//    first - now, second - 2:00, third - 5:00
 	Error1("First " + long_message)
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	for i, s := range suffixes {
 		testRotatableLogExists(t, "rotatable"+s, i < 1, false, false, "3 minutes")
 	}
 	/* -- Shift the time the time by 3 minutes - the time to the rotate: */
 	//    first - now, second - now, third - 2:00
 	Error1("Second " + long_message)
-	timesrc.ShiftTime(3 * time.Minute)
+	timesrc.Advance(3 * time.Minute)
 	for i, s := range suffixes {
 		testRotatableLogExists(t, "rotatable"+s, i < 2, i < 1, false, "6 minutes")
 	}
 	/* -- Shift the time by 4 minutes - the time to the rotate: */
 	//    first - now, second - 1:00, third - now
 	Error1("Third " + long_message)
-	timesrc.ShiftTime(4 * time.Minute)
+	timesrc.Advance(4 * time.Minute)
 	for i, s := range suffixes {
 		testRotatableLogExists(t, "rotatable"+s, true, i < 1, i < 1, "10 minutes")
 	}
 	/* -- Shift the time by one and half minutes - the time to the rotate: */
 	//   first - 0:30, second - now, third - 7:30
 	Error1("Fourth " + long_message)
-	timesrc.ShiftTime(90 * time.Second)
+	timesrc.Advance(90 * time.Second)
 	for i, s := range suffixes {
 		testRotatableLogExists(t, "rotatable"+s, true, i < 2, i < 1, "11 minutes 30 seconds")
 	}
 
 }
 
-func rotatableLogInit(t *testing.T, suffixes ...string) *mockTimeSource {
+func rotatableLogInit(t *testing.T, suffixes ...string) *MockTimeSource {
 	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
-	timesrc := &mockTimeSource{
-		now: now,
-	}
+	timesrc := NewMockTimeSource(now)
 	rotatableLogClean(suffixes...)
 	for _, s := range suffixes {
 		// The function  testRotatableLogExists assumes that the main log file always exists.
@@ -180,3 +181,179 @@ func testRotatableLogExistsImpl(t *testing.T, fileName string, shouldExist bool,
 		t.Fatalf("Error - %s: The file '%s' exists.", messageId, fileName)
 	}
 }
+
+func TestRotatableCompression(t *testing.T) {
+	const long_message_base = " very very very very very very long long long long long long long long long long long long long long long long long long"
+	const long_message = long_message_base + long_message_base + " message"
+
+	names := []string{
+		"rotatable.log", "rotatable.log.1", "rotatable.log.1.gz",
+		"rotatable.log.1.gz.tmp", "rotatable.log.2", "rotatable.log.2.gz",
+		"rotatable.log.3.gz",
+	}
+	clean := func() {
+		for _, name := range names {
+			os.Remove(name)
+		}
+	}
+	clean()
+	defer func() {
+		clean()
+		Destroy()
+	}()
+
+	/* -- an uncompressed generation and a half-written archive left
+	   by a crash */
+	ioutil.WriteFile("rotatable.log.1", []byte("crashed\n"), 0644)
+	ioutil.WriteFile("rotatable.log.1.gz.tmp", []byte("garbage"), 0644)
+
+	timesrc := rotatableLogInit(t)
+	AddRotatableFileLoggerWithOptions(
+		"file", "", MaskAll, 5, "rotatable.log", false,
+		RotatableFileOptions{
+			MaxSize:       200,
+			CheckInterval: 2 * time.Minute,
+			Compression:   CompressGzip,
+		})
+
+	Error1("First " + long_message)
+	timesrc.Advance(3 * time.Minute)
+	testRotatableFilesExist(t, names, "rotatable.log", "rotatable.log.1.gz", "rotatable.log.2.gz")
+	if content := readGzipFile(t, "rotatable.log.2.gz"); content != "crashed\n" {
+		t.Errorf("unexpected content of the second generation: %q", content)
+	}
+	if content := readGzipFile(t, "rotatable.log.1.gz"); !strings.Contains(content, "First") {
+		t.Errorf("unexpected content of the first generation: %q", content)
+	}
+
+	Error1("Second " + long_message)
+	timesrc.Advance(3 * time.Minute)
+	testRotatableFilesExist(t, names, "rotatable.log", "rotatable.log.1.gz", "rotatable.log.2.gz", "rotatable.log.3.gz")
+	if content := readGzipFile(t, "rotatable.log.1.gz"); !strings.Contains(content, "Second") {
+		t.Errorf("unexpected content of the first generation: %q", content)
+	}
+}
+
+func testRotatableFilesExist(t *testing.T, names []string, existing ...string) {
+	for _, name := range names {
+		shouldExist := false
+		for _, e := range existing {
+			shouldExist = shouldExist || e == name
+		}
+		testRotatableLogExistsImpl(t, name, shouldExist, "compression")
+	}
+}
+
+func readGzipFile(t *testing.T, name string) string {
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("cannot open %s: %s", name, err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("cannot read %s: %s", name, err)
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("cannot read %s: %s", name, err)
+	}
+	return string(content)
+}
+
+func TestRotatableRetention(t *testing.T) {
+	const long_message_base = " very very very very very very long long long long long long long long long long long long long long long long long long"
+	const long_message = long_message_base + long_message_base + " message"
+
+	defer func() {
+		rotatableLogClean("")
+		Destroy()
+	}()
+
+	timesrc := rotatableLogInit(t, "")
+	AddRotatableFileLoggerWithOptions(
+		"file", "", MaskAll, 5, "rotatable.log", false,
+		RotatableFileOptions{
+			MaxSize:       200,
+			CheckInterval: 2 * time.Minute,
+			Retention:     RetentionPolicy{MaxGenerations: 2},
+		})
+
+	for i, message := range []string{"First", "Second", "Third", "Fourth"} {
+		Error1(message + long_message)
+		timesrc.Advance(3 * time.Minute)
+		testRotatableLogExists(t, "rotatable", true, i >= 1, false, message)
+	}
+}
+
+func TestRotatableSchedule(t *testing.T) {
+	defer func() {
+		rotatableLogClean("")
+		Destroy()
+	}()
+
+	/* -- 14:02:00 */
+	timesrc := rotatableLogInit(t, "")
+	AddRotatableFileLoggerWithOptions(
+		"file", "", MaskAll, 5, "rotatable.log", false,
+		RotatableFileOptions{
+			Schedule: RotationSchedule{
+				Period: RotateDaily,
+				At:     15 * time.Hour,
+			},
+		})
+
+	Error1("First error")
+	timesrc.Advance(30 * time.Minute)
+	testRotatableLogExists(t, "rotatable", false, false, false, "14:32")
+
+	/* -- the scheduled time */
+	timesrc.Advance(30 * time.Minute)
+	testRotatableLogExists(t, "rotatable", true, false, false, "15:02")
+
+	/* -- the empty file is not rotated */
+	timesrc.Advance(24 * time.Hour)
+	testRotatableLogExists(t, "rotatable", true, false, false, "next day 15:02")
+
+	/* -- the quiet day */
+	Error1("Second error")
+	timesrc.Advance(23 * time.Hour)
+	testRotatableLogExists(t, "rotatable", true, false, false, "third day 14:02")
+	timesrc.Advance(time.Hour)
+	testRotatableLogExists(t, "rotatable", true, true, false, "third day 15:02")
+}
+
+func TestRotatableScheduleWithSize(t *testing.T) {
+	const long_message_base = " very very very very very very long long long long long long long long long long long long long long long long long long"
+	const long_message = long_message_base + long_message_base + " message"
+
+	defer func() {
+		rotatableLogClean("")
+		Destroy()
+	}()
+
+	/* -- 14:02:00 */
+	timesrc := rotatableLogInit(t, "")
+	AddRotatableFileLoggerWithOptions(
+		"file", "", MaskAll, 5, "rotatable.log", false,
+		RotatableFileOptions{
+			MaxSize:       200,
+			CheckInterval: 2 * time.Minute,
+			Schedule: RotationSchedule{
+				Period: RotateHourly,
+				At:     30 * time.Minute,
+			},
+		})
+
+	/* -- the size rotation */
+	Error1("First " + long_message)
+	timesrc.Advance(3 * time.Minute)
+	testRotatableLogExists(t, "rotatable", true, false, false, "14:05")
+
+	/* -- the scheduled rotation */
+	Error1("Short error")
+	timesrc.Advance(20 * time.Minute)
+	testRotatableLogExists(t, "rotatable", true, false, false, "14:25")
+	timesrc.Advance(6 * time.Minute)
+	testRotatableLogExists(t, "rotatable", true, true, false, "14:31")
+}
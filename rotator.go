@@ -24,49 +24,136 @@ type RotatableFileHolder interface {
 	FileHolder
 }
 
+// Request to add or remove a rotator
+type rotatorRequest struct {
+	rotator LogRotator
+	done    chan struct{}
+}
+
 type logsRotatorStarter struct {
-	timesrc     TimeSource
-	nextCheck   time.Time
-	rotators    *rotatorHeap // heap - first item is always the first scheduled ation
-	destroyChan chan struct{}
-	addNewChan  chan LogRotator
-	once        sync.Once
+	timesrc      TimeSource
+	nextCheck    time.Time
+	rotators     *rotatorHeap // heap - first item is always the first scheduled ation
+	destroyChan  chan struct{}
+	finishedChan chan struct{} // closed when the main thread finishes
+	addNewChan   chan rotatorRequest
+	removeChan   chan rotatorRequest
+	timeChan     chan chan struct{} // changes of mocked time, the channel is closed when the due rotators finish
+	mutex        sync.Mutex
+	running      bool
+	destroyed    bool
 }
 
-func newRotators(timesrc TimeSource) logsRotatorStarter {
-	return logsRotatorStarter{timesrc: timesrc, rotators: &rotatorHeap{}}
+func newRotators(timesrc TimeSource) *logsRotatorStarter {
+	return &logsRotatorStarter{
+		timesrc:      timesrc,
+		rotators:     &rotatorHeap{},
+		destroyChan:  make(chan struct{}),
+		finishedChan: make(chan struct{}),
+		addNewChan:   make(chan rotatorRequest),
+		removeChan:   make(chan rotatorRequest),
+		timeChan:     make(chan chan struct{}),
+	}
 }
 
-// Add the rotator to starter. See LogRotator inteface.
-func (this *logsRotatorStarter) Add(rotator LogRotator) {
-	this.once.Do(func() {
+// Start the main thread if it isn't running yet
+//
+// Returns:
+//     false if the starter has been already destroyed
+func (this *logsRotatorStarter) start() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.destroyed {
+		return false
+	}
+	if !this.running {
+		this.running = true
 		this.nextCheck = this.timesrc.Now().Add(time.Hour)
-		this.addNewChan = make(chan LogRotator)
-		this.destroyChan = make(chan struct{})
 		go this.mainThread()
-	})
-	this.addNewChan <- rotator
+	}
+	return true
+}
+
+func (this *logsRotatorStarter) isRunning() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.running
+}
+
+// Add the rotator to starter. See LogRotator inteface.
+func (this *logsRotatorStarter) Add(rotator LogRotator) {
+	if rotator == nil || !this.start() {
+		return
+	}
+	if linked, ok := rotator.(linkedRotator); ok {
+		linked.linkRotatorStarter(this)
+	}
+	/* -- wait until the rotator is scheduled, the time may be mocked */
+	done := make(chan struct{})
+	select {
+	case this.addNewChan <- rotatorRequest{rotator: rotator, done: done}:
+		<-done
+	case <-this.finishedChan:
+	}
+}
+
+// Remove the rotator from the starter
+//
+// When the method returns, the rotator isn't running and it won't
+// be run anymore.
+func (this *logsRotatorStarter) Remove(rotator LogRotator) {
+	if !this.isRunning() {
+		/* -- nothing has been added */
+		return
+	}
+	done := make(chan struct{})
+	select {
+	case this.removeChan <- rotatorRequest{rotator: rotator, done: done}:
+		<-done
+	case <-this.finishedChan:
+	}
 }
 
 // See function AfterChangeMockedTime
 func (this *logsRotatorStarter) OnMockedTimeChanged(wait bool) {
-	if this.rotators == nil || len(*this.rotators) == 0 {
+	if !this.isRunning() {
+		/* -- no rotator has been added yet */
 		return
 	}
-	this.Add(nil)
-	if wait {
-		// wait to next mainThread step
-		for this.nextCheck.Before(this.timesrc.Now()) {
-			time.Sleep(10 * time.Microsecond)
+	done := make(chan struct{})
+	select {
+	case this.timeChan <- done:
+		if wait {
+			<-done
 		}
+	case <-this.finishedChan:
 	}
 }
 
+// Stop the main thread and wait for it
+//
+// The rotators are not run after the method returns. Following calls
+// of the methods of the starter do nothing.
 func (this *logsRotatorStarter) Destroy() {
-	this.destroyChan <- struct{}{}
+	this.mutex.Lock()
+	if this.destroyed {
+		this.mutex.Unlock()
+		return
+	}
+	this.destroyed = true
+	running := this.running
+	this.mutex.Unlock()
+
+	close(this.destroyChan)
+	if running {
+		<-this.finishedChan
+	} else {
+		close(this.finishedChan)
+	}
 }
 
 func (this *logsRotatorStarter) mainThread() {
+	defer close(this.finishedChan)
 	heap.Init(this.rotators)
 	var timer *time.Timer
 	for {
@@ -75,14 +162,24 @@ func (this *logsRotatorStarter) mainThread() {
 		case <-this.destroyChan:
 			this.abortSleep(timer)
 			return
-		case newRotator := <-this.addNewChan:
-			if newRotator != nil {
-				checkTime := newRotator.GetNextCheckTime(this.timesrc)
-				if checkTime.Before(this.nextCheck) {
-					this.nextCheck = checkTime
+		case addition := <-this.addNewChan:
+			checkTime := addition.rotator.GetNextCheckTime(this.timesrc)
+			if checkTime.Before(this.nextCheck) {
+				this.nextCheck = checkTime
+			}
+			heap.Push(this.rotators, &rotatorWithTime{rotator: addition.rotator, nextCheckTime: checkTime})
+			close(addition.done)
+		case removal := <-this.removeChan:
+			for i := len(*this.rotators) - 1; i >= 0; i-- {
+				if (*this.rotators)[i].rotator == removal.rotator {
+					heap.Remove(this.rotators, i)
 				}
-				heap.Push(this.rotators, &rotatorWithTime{rotator: newRotator, nextCheckTime: checkTime})
 			}
+			close(removal.done)
+		case done := <-this.timeChan:
+			/* -- run the rotators due at the new time and acknowledge it */
+			this.step()
+			close(done)
 		case <-timer.C:
 			this.step()
 		}
@@ -163,5 +260,56 @@ func (this *rotatorHeap) Push(x interface{}) {
 }
 
 func (this *rotatorHeap) Pop() interface{} {
-	panic("unused")
+	last := len(*this) - 1
+	item := (*this)[last]
+	(*this)[last] = nil
+	*this = (*this)[:last]
+	return item
+}
+
+// Rotator which removes itself from the starters
+//
+// If a rotator implements this interface, the starter passes itself
+// to the rotator when the rotator is added. The rotator is expected
+// to remove itself when it's destroyed.
+type linkedRotator interface {
+	linkRotatorStarter(starter *logsRotatorStarter)
+}
+
+// Starters a rotator has been added to
+//
+// The structure is meant to be embedded into rotators implementing
+// the linkedRotator interface.
+type rotatorLinks struct {
+	mutex    sync.Mutex
+	starters []*logsRotatorStarter
+}
+
+func (this *rotatorLinks) linkRotatorStarter(
+	starter *logsRotatorStarter,
+) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for _, linked := range this.starters {
+		if linked == starter {
+			return
+		}
+	}
+	this.starters = append(this.starters, starter)
+}
+
+// Remove the rotator from all linked starters
+//
+// The function waits until the rotator isn't run by any starter.
+// It mustn't be invoked by the rotator itself.
+func (this *rotatorLinks) unlinkRotatorStarters(
+	rotator LogRotator,
+) {
+	this.mutex.Lock()
+	starters := this.starters
+	this.starters = nil
+	this.mutex.Unlock()
+	for _, starter := range starters {
+		starter.Remove(rotator)
+	}
 }
@@ -0,0 +1,93 @@
+package goolog2_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestRotatorNoGoroutineLeak(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		InitWithTimeSource("testlog", NewMockTimeSource(now))
+		AddRotatableFileLogger(
+			"file", "", MaskAll, 5, filepath.Join(dir, "rotatable.log"), false, 200, time.Minute)
+		AddReopenWatcher(time.Second)
+		Info1("message")
+		if i%2 == 0 {
+			Destroy()
+		}
+	}
+	Destroy()
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines leak: %d before, %d after", before, after)
+	}
+}
+
+func TestRemoveLogRotator(t *testing.T) {
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+	InitWithTimeSource("testlog", timesrc)
+	defer Destroy()
+
+	first := &countingRotator{interval: time.Minute}
+	second := &countingRotator{interval: time.Minute}
+	AddLogRotator(first)
+	AddLogRotator(second)
+	timesrc.Advance(time.Minute)
+	RemoveLogRotator(first)
+	RemoveLogRotator(first)
+	timesrc.Advance(time.Minute)
+	if first.count != 1 || second.count != 2 {
+		t.Errorf("unexpected counts: %d, %d", first.count, second.count)
+	}
+}
+
+func TestRotatorOfRemovedLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+	InitWithTimeSource("testlog", timesrc)
+	defer Destroy()
+
+	/* -- the holder is released only if the rotator is unregistered */
+	released := make(chan struct{})
+	func() {
+		f := NewRotatableFile(filepath.Join(dir, "rotatable.log"), false, 10, time.Minute)
+		defer f.Unref()
+		runtime.SetFinalizer(f, func(interface{}) { close(released) })
+		AddLogRotator(f)
+		AddLogger(
+			"file", "", MaskAll, 5,
+			NewFileLogger(timesrc, f, NewLineFormatterDefault(false)))
+	}()
+	Info1("message")
+	RemoveLogger("file")
+	timesrc.Advance(2 * time.Minute)
+
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		select {
+		case <-released:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("the rotator of the removed logger is still registered")
+}
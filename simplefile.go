@@ -7,7 +7,10 @@ import (
 )
 
 type simpleFile struct {
+	errorSink
+	path     string
 	writer   FileWriter
+	tracker  errorTrackingWriter
 	mutex    sync.Mutex
 	sync     bool
 	refcount int32
@@ -26,19 +29,54 @@ func NewSimpleFile(
 	filepath string,
 	sync bool,
 ) FileHolder {
+	// If the file cannot be opened, the logging just simply doesn't work.
+	// The error is reported when an error handler is set.
+	holder, err := newSimpleFile(filepath, sync)
+	holder.reportError(err)
+	return holder
+}
+
+// Create new simple file holder and report the opening error
+//
+// Parameters:
+//     filepath: name of the logging file
+//     sync: if true, the stream is flushed after every message
+// Returns:
+//     the new file holder or nil if the file cannot be opened
+//     the error
+// Note: the reference counter is set to 1. You have to invoke Unref()
+//     to clean up the holder.
+func NewSimpleFileE(
+	filepath string,
+	sync bool,
+) (FileHolder, error) {
+	holder, err := newSimpleFile(filepath, sync)
+	if err != nil {
+		holder.Unref()
+		return nil, err
+	}
+	return holder, nil
+}
+
+func newSimpleFile(
+	filepath string,
+	sync bool,
+) (*simpleFile, error) {
 	holder := &simpleFile{
+		path:     filepath,
 		sync:     sync,
 		refcount: 1,
 	}
 
-	// I ignore the error here - if the file cannot be opened, the logging
-	// just simply doesn't work. However, if I cannot open the logging
-	// file, I cannot report the error.
-	file, _ := os.OpenFile(
+	file, err := os.OpenFile(
 		filepath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file = nil
+	}
 	holder.writer = newSimpleFileWriter(file, true)
+	registerReopenableFile(holder)
 
-	return holder
+	return holder, err
 }
 
 // Create new simple file holder working over an opened file handle
@@ -64,15 +102,25 @@ func NewSimpleFileHandle(
 func (this *simpleFile) AccessWriter(
 	functor func(writer FileWriter),
 ) {
-	if this.writer != nil {
-		/* --  The lock avoids inter-mixing of logging lines */
+	/* --  The lock avoids inter-mixing of logging lines */
+	/* -- the deferred unlock keeps the holder usable if the functor panics */
+	err := func() error {
 		this.mutex.Lock()
 		defer this.mutex.Unlock()
-		functor(this.writer)
+		if this.writer == nil {
+			return nil
+		}
+		this.tracker.reset(this.writer)
+		defer this.tracker.reset(nil)
+		functor(&this.tracker)
 		if this.sync {
 			this.writer.Sync()
 		}
-	}
+		return this.tracker.err
+	}()
+
+	/* -- the handler is invoked out of the lock, it may log */
+	this.reportError(err)
 }
 
 func (this *simpleFile) Ref() FileHolder {
@@ -82,8 +130,48 @@ func (this *simpleFile) Ref() FileHolder {
 
 func (this *simpleFile) Unref() {
 	refcount := atomic.AddInt32(&this.refcount, -1)
-	if refcount == 0 && this.writer != nil {
-		this.writer.Close()
-		this.writer = nil
+	if refcount == 0 {
+		unregisterReopenableFile(this)
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		if this.writer != nil {
+			this.writer.Close()
+			this.writer = nil
+		}
+	}
+}
+
+func (this *simpleFile) Reopen() error {
+	if this.path == "" {
+		/* -- the holder works over a file handle */
+		return nil
+	}
+	err := this.reopen()
+	this.reportError(err)
+	return err
+}
+
+func (this *simpleFile) reopen() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.writer == nil {
+		return nil
+	}
+	this.writer.Close()
+	file, err := os.OpenFile(
+		this.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file = nil
+	}
+	this.writer = newSimpleFileWriter(file, true)
+	return err
+}
+
+func (this *simpleFile) PathChanged() bool {
+	if this.path == "" {
+		return false
 	}
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return writerPathChanged(this.writer, this.path)
 }
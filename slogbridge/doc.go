@@ -0,0 +1,11 @@
+// Package slogbridge forwards records of the log/slog package into
+// a goolog2 dispatcher.
+//
+// The package requires Go 1.21 or newer (the log/slog package). With
+// older compilers the package is empty.
+//
+// Usage:
+//
+//     slog.SetDefault(slog.New(slogbridge.NewHandler(
+//         goolog2.Global().Dispatcher(), &slogbridge.Options{Subsystem: "lib"})))
+package slogbridge
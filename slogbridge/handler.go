@@ -0,0 +1,192 @@
+//go:build go1.21
+// +build go1.21
+
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Staon/goolog2"
+)
+
+// Level of critical errors
+//
+// The slog package doesn't define a level above errors. Records
+// at this level or higher are logged with the Critical severity.
+const LevelCritical = slog.LevelError + 4
+
+// Options of the handler
+type Options struct {
+	// Subsystem of the records. Can be empty.
+	Subsystem goolog2.Subsystem
+
+	// Verbosity of the records. Zero means the verbosity 1.
+	Verbosity goolog2.Verbosity
+
+	// Custom mapping of slog levels. If it's nil, the levels are
+	// mapped onto severities by DefaultLevelMapping and the verbosity
+	// is taken from the Verbosity option.
+	LevelMapping func(level slog.Level) (goolog2.Severity, goolog2.Verbosity)
+
+	// If it's true, the groups make nested subsystems (the group "pool"
+	// of the subsystem "db" logs into the subsystem "db/pool"). Otherwise
+	// the keys of the attributes are prefixed by the groups ("pool.size").
+	GroupsAsSubsystems bool
+}
+
+// Map a slog level onto a severity
+//
+// The levels LevelCritical, slog.LevelError, slog.LevelWarn, slog.LevelInfo
+// and slog.LevelDebug are the lower bounds of the severities Critical,
+// Error, Warning, Info and Debug.
+func DefaultLevelMapping(
+	level slog.Level,
+) goolog2.Severity {
+	switch {
+	case level >= LevelCritical:
+		return goolog2.Critical
+	case level >= slog.LevelError:
+		return goolog2.Error
+	case level >= slog.LevelWarn:
+		return goolog2.Warning
+	case level >= slog.LevelInfo:
+		return goolog2.Info
+	default:
+		return goolog2.Debug
+	}
+}
+
+// The slog handler forwarding records into a goolog2 dispatcher
+//
+// The attributes are passed as structured fields (see goolog2.FieldsObject).
+// The time of the records is ignored, the loggers use their own time
+// source.
+type Handler struct {
+	dispatcher goolog2.LogDispatcher
+	options    Options
+	subsystem  goolog2.Subsystem
+	prefix     string
+	fields     []goolog2.Field
+}
+
+// Create new slog handler
+//
+// Parameters:
+//     dispatcher: the dispatcher receiving the records
+//     options: options of the handler. Can be nil.
+// Returns:
+//     the handler
+func NewHandler(
+	dispatcher goolog2.LogDispatcher,
+	options *Options,
+) *Handler {
+	handler := &Handler{
+		dispatcher: dispatcher,
+	}
+	if options != nil {
+		handler.options = *options
+	}
+	if handler.options.Verbosity == 0 {
+		handler.options.Verbosity = 1
+	}
+	handler.subsystem = handler.options.Subsystem
+	return handler
+}
+
+// Get severity and verbosity of a level
+func (this *Handler) mapLevel(
+	level slog.Level,
+) (goolog2.Severity, goolog2.Verbosity) {
+	if this.options.LevelMapping != nil {
+		return this.options.LevelMapping(level)
+	}
+	return DefaultLevelMapping(level), this.options.Verbosity
+}
+
+// See slog.Handler
+func (this *Handler) Enabled(
+	ctx context.Context,
+	level slog.Level,
+) bool {
+	severity, verbosity := this.mapLevel(level)
+	return this.dispatcher.Enabled(this.subsystem, severity, verbosity)
+}
+
+// See slog.Handler
+func (this *Handler) Handle(
+	ctx context.Context,
+	record slog.Record,
+) error {
+	severity, verbosity := this.mapLevel(record.Level)
+	fields := make([]goolog2.Field, len(this.fields), len(this.fields)+record.NumAttrs())
+	copy(fields, this.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, this.prefix, attr)
+		return true
+	})
+	goolog2.DispatcherLogFields(
+		this.dispatcher, this.subsystem, severity, verbosity, record.Message, fields...)
+	return nil
+}
+
+// See slog.Handler
+func (this *Handler) WithAttrs(
+	attrs []slog.Attr,
+) slog.Handler {
+	if len(attrs) == 0 {
+		return this
+	}
+	handler := *this
+	handler.fields = append([]goolog2.Field(nil), this.fields...)
+	for _, attr := range attrs {
+		handler.fields = appendAttr(handler.fields, this.prefix, attr)
+	}
+	return &handler
+}
+
+// See slog.Handler
+func (this *Handler) WithGroup(
+	name string,
+) slog.Handler {
+	if name == "" {
+		return this
+	}
+	handler := *this
+	if this.options.GroupsAsSubsystems {
+		if handler.subsystem == "" {
+			handler.subsystem = goolog2.Subsystem(name)
+		} else {
+			handler.subsystem += goolog2.Subsystem("/" + name)
+		}
+	} else {
+		handler.prefix = this.prefix + name + "."
+	}
+	return &handler
+}
+
+// Convert an attribute into fields
+//
+// Groups are flattened, the keys of their members are prefixed
+// by the group name. Empty attributes are ignored.
+func appendAttr(
+	fields []goolog2.Field,
+	prefix string,
+	attr slog.Attr,
+) []goolog2.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		if attr.Key != "" {
+			prefix = prefix + attr.Key + "."
+		}
+		for _, member := range group {
+			fields = appendAttr(fields, prefix, member)
+		}
+		return fields
+	}
+	return append(fields, goolog2.Field{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
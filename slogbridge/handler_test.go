@@ -0,0 +1,124 @@
+//go:build go1.21
+// +build go1.21
+
+package slogbridge_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Staon/goolog2"
+	"github.com/Staon/goolog2/goolog2test"
+	. "github.com/Staon/goolog2/slogbridge"
+)
+
+func newTestLogger(
+	options *Options,
+) (*slog.Logger, *goolog2test.MemoryLogger, goolog2.LogDispatcher) {
+	dispatcher := goolog2.NewLogDispatcher("testlog")
+	memory := goolog2test.NewMemoryLogger()
+	dispatcher.AddLogger("memory", "", goolog2.MaskStd, 2, memory)
+	return slog.New(NewHandler(dispatcher, options)), memory, dispatcher
+}
+
+func TestHandlerLevels(t *testing.T) {
+	t.Parallel()
+
+	logger, memory, dispatcher := newTestLogger(&Options{Subsystem: "lib"})
+	defer dispatcher.Destroy()
+
+	logger.Log(context.Background(), LevelCritical, "critical")
+	logger.Error("error")
+	logger.Warn("warning")
+	logger.Info("info")
+	logger.Debug("debug")
+	if logger.Enabled(context.Background(), slog.LevelDebug) || !logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("the filters of the dispatcher aren't honored")
+	}
+
+	records := memory.Records()
+	expected := []goolog2.Severity{goolog2.Critical, goolog2.Error, goolog2.Warning, goolog2.Info}
+	if len(records) != len(expected) {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	for i, record := range records {
+		if record.Severity != expected[i] || record.Verbosity != 1 || record.Subsystem != "lib" {
+			t.Errorf("unexpected record: %+v", record)
+		}
+	}
+}
+
+func TestHandlerLevelMapping(t *testing.T) {
+	t.Parallel()
+
+	logger, memory, dispatcher := newTestLogger(&Options{
+		LevelMapping: func(level slog.Level) (goolog2.Severity, goolog2.Verbosity) {
+			if level < slog.LevelInfo {
+				return goolog2.Info, 2
+			}
+			return goolog2.Info, 1
+		},
+	})
+	defer dispatcher.Destroy()
+
+	logger.Debug("detail")
+	memory.ExpectLogged(t, goolog2.Info, "detail")
+	if records := memory.Records(); len(records) != 1 || records[0].Verbosity != 2 {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
+
+func TestHandlerAttributes(t *testing.T) {
+	t.Parallel()
+
+	logger, memory, dispatcher := newTestLogger(nil)
+	defer dispatcher.Destroy()
+
+	logger.With("service", "api").WithGroup("req").Info(
+		"done",
+		"status", 200,
+		slog.Group("timing", slog.Duration("total", time.Second)),
+		slog.Attr{})
+
+	records := memory.Records()
+	if len(records) != 1 {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	expected := []goolog2.Field{
+		{Key: "service", Value: "api"},
+		{Key: "req.status", Value: int64(200)},
+		{Key: "req.timing.total", Value: time.Second},
+	}
+	fields := records[0].Fields
+	if len(fields) != len(expected) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("unexpected field %d: %v", i, fields[i])
+		}
+	}
+}
+
+func TestHandlerGroupsAsSubsystems(t *testing.T) {
+	t.Parallel()
+
+	logger, memory, dispatcher := newTestLogger(
+		&Options{Subsystem: "db", GroupsAsSubsystems: true})
+	defer dispatcher.Destroy()
+	dispatcher.SetLoggerSubsystemVerbosity("memory", "db/pool", goolog2.MaskAll, 5)
+
+	pool := logger.WithGroup("pool")
+	if !pool.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("the override of the subsystem isn't honored")
+	}
+	pool.Debug("size", "idle", 3)
+
+	records := memory.Records()
+	if len(records) != 1 || records[0].Subsystem != "db/pool" ||
+		len(records[0].Fields) != 1 || records[0].Fields[0].Key != "idle" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
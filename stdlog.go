@@ -0,0 +1,182 @@
+package goolog2
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// Maximal length of an unfinished line buffered by the log writer
+//
+// If the buffer is full, its content is logged as a line.
+const maxLogWriterLine = 64 * 1024
+
+// Writer dispatching written lines as log messages
+//
+// The writer splits the written data into lines. Every line is logged
+// as one message (the line break isn't part of the message). An unfinished
+// line is buffered until the rest of the line is written or the writer
+// is closed. Empty lines are ignored.
+//
+// The writer is safe to be used by concurrent goroutines.
+type LogWriter struct {
+	dispatcher LogDispatcher
+	subsystem  Subsystem
+	severity   Severity
+	verbosity  Verbosity
+	mutex      sync.Mutex
+	buffer     []byte
+}
+
+// Create new log writer
+//
+// Parameters:
+//     dispatcher: the dispatcher receiving the messages
+//     subsystem: logging subsystem. Can be empty.
+//     severity: severity of the messages
+//     verbosity: verbosity of the messages
+// Returns:
+//     the writer
+func NewLogWriter(
+	dispatcher LogDispatcher,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) *LogWriter {
+	return &LogWriter{
+		dispatcher: dispatcher,
+		subsystem:  subsystem,
+		severity:   severity,
+		verbosity:  verbosity,
+	}
+}
+
+// See io.Writer
+func (this *LogWriter) Write(
+	data []byte,
+) (int, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	written := len(data)
+	for len(data) > 0 {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 {
+			/* -- unfinished line, split it if it's too long */
+			free := maxLogWriterLine - len(this.buffer)
+			if len(data) < free {
+				this.buffer = append(this.buffer, data...)
+				break
+			}
+			this.buffer = append(this.buffer, data[:free]...)
+			this.flush()
+			data = data[free:]
+			continue
+		}
+		this.buffer = append(this.buffer, data[:index]...)
+		this.flush()
+		data = data[index+1:]
+	}
+	return written, nil
+}
+
+// Log the unfinished line
+//
+// The writer can be used after it's closed.
+func (this *LogWriter) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.flush()
+	return nil
+}
+
+// Log the buffered line
+//
+// The mutex must be locked.
+func (this *LogWriter) flush() {
+	line := bytes.TrimSuffix(this.buffer, []byte{'\r'})
+	if len(line) > 0 {
+		DispatcherLogMessage(
+			this.dispatcher, this.subsystem, this.severity, this.verbosity, string(line))
+	}
+	this.buffer = this.buffer[:0]
+}
+
+// Create a standard logger writing into the global log
+//
+// The logger writes through a LogWriter. The flags of the logger are
+// zero and the prefix is empty, the time is added by the goolog2 loggers.
+// The logger follows the global log even if it's initialized again.
+//
+// Parameters:
+//     subsystem: logging subsystem. Can be empty.
+//     severity: severity of the messages
+//     verbosity: verbosity of the messages
+// Returns:
+//     the standard logger
+func NewStdLogger(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) *log.Logger {
+	return log.New(
+		NewLogWriter(&globalDispatcher{}, subsystem, severity, verbosity), "", 0)
+}
+
+// Redirect the default logger of the log package into the global log
+//
+// The log package of Go 1.12 cannot report the current output of the default
+// logger, hence the restoring function sets the standard error output.
+// Use RedirectStdLogFrom if the application has changed the output.
+//
+// Parameters:
+//     subsystem: logging subsystem. Can be empty.
+//     severity: severity of the messages
+//     verbosity: verbosity of the messages
+// Returns:
+//     a function restoring the previous flags and prefix of the default
+//     logger and setting its output to os.Stderr
+func RedirectStdLog(
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) func() {
+	return RedirectStdLogFrom(os.Stderr, subsystem, severity, verbosity)
+}
+
+// Redirect the default logger of the log package into the global log
+//
+// Parameters:
+//     previous: the current output of the default logger. It's restored
+//         by the returned function.
+//     subsystem: logging subsystem. Can be empty.
+//     severity: severity of the messages
+//     verbosity: verbosity of the messages
+// Returns:
+//     a function restoring the previous output, flags and prefix of
+//     the default logger
+func RedirectStdLogFrom(
+	previous io.Writer,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+) func() {
+	flags := log.Flags()
+	prefix := log.Prefix()
+	writer := NewLogWriter(&globalDispatcher{}, subsystem, severity, verbosity)
+	log.SetOutput(writer)
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			log.SetOutput(previous)
+			log.SetFlags(flags)
+			log.SetPrefix(prefix)
+			writer.Close()
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package goolog2_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/Staon/goolog2"
+	"github.com/Staon/goolog2/goolog2test"
+)
+
+func TestLogWriter(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	memory := goolog2test.NewMemoryLogger()
+	dispatcher.AddLogger("memory", "", MaskAll, 5, memory)
+
+	writer := NewLogWriter(dispatcher, "driver", Warning, 3)
+	writer.Write([]byte("first line\nsecond "))
+	writer.Write([]byte("line\r\n\nthird"))
+	if len(memory.Records()) != 2 {
+		t.Errorf("the unfinished line is logged")
+	}
+	writer.Close()
+	writer.Write([]byte(strings.Repeat("x", 70*1024)))
+	writer.Close()
+
+	records := memory.Records()
+	expected := []string{
+		"first line", "second line", "third",
+		strings.Repeat("x", 64*1024), strings.Repeat("x", 6*1024),
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("unexpected records: %d", len(records))
+	}
+	for i, record := range records {
+		if record.Line != expected[i] || record.Subsystem != "driver" ||
+			record.Severity != Warning || record.Verbosity != 3 {
+			t.Errorf("unexpected record %d: %d bytes", i, len(record.Line))
+		}
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	defer goolog2test.Install(t)()
+	memory := goolog2test.Installed(t)
+
+	restore := RedirectStdLog("std", Info, 2)
+	log.Printf("value %d", 42)
+	NewStdLogger("server", Error, 1).Print("accept failed")
+	restore()
+	var original bytes.Buffer
+	log.SetOutput(&original)
+	log.Print("not redirected")
+	log.SetOutput(os.Stderr)
+
+	memory.ExpectLogged(t, Info, "value 42")
+	memory.ExpectLogged(t, Error, "accept failed")
+	memory.ExpectNotLogged(t, Info, "not redirected")
+	if !strings.HasSuffix(original.String(), "not redirected\n") {
+		t.Errorf("the output isn't restored: %q", original.String())
+	}
+}
+
+func TestRedirectStdLogFrom(t *testing.T) {
+	defer goolog2test.Install(t)()
+	memory := goolog2test.Installed(t)
+
+	var previous bytes.Buffer
+	log.SetOutput(&previous)
+	defer log.SetOutput(os.Stderr)
+	restore := RedirectStdLogFrom(&previous, "std", Info, 2)
+	log.Print("redirected")
+	restore()
+	log.Print("not redirected")
+
+	memory.ExpectLogged(t, Info, "redirected")
+	memory.ExpectNotLogged(t, Info, "not redirected")
+	if !strings.HasSuffix(previous.String(), "not redirected\n") {
+		t.Errorf("the previous output isn't restored: %q", previous.String())
+	}
+}
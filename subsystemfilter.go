@@ -0,0 +1,137 @@
+package goolog2
+
+import (
+	"strings"
+)
+
+// Decision of a subsystem rule
+type subsystemDecision uint8
+
+const (
+	subsystemUnset subsystemDecision = iota
+	subsystemInclude
+	subsystemExclude
+)
+
+// Rules attached to one node of the subsystem hierarchy
+type subsystemRule struct {
+	/* -- decision for the subsystem itself */
+	self subsystemDecision
+	/* -- decision for the nested subsystems */
+	descendants subsystemDecision
+}
+
+// Compiled subsystem pattern of a logger
+//
+// The subsystems are hierarchical, the levels are separated by slashes
+// ("db/pool"). The pattern is a comma separated list of items:
+//     db ........ the subsystem db and all nested subsystems
+//     db/* ...... only the nested subsystems of db
+//     * ......... all subsystems
+//     !db/query . exclusion of the subsystem and its nested subsystems
+//     !db/* ..... exclusion of the nested subsystems
+// The most specific (the longest) matching item decides. If an include
+// and an exclude item are equally specific, the exclusion wins. If the
+// pattern contains only exclusions, all other subsystems are matched.
+//
+// The matching cost depends on the depth of the logged subsystem,
+// not on the number of items of the pattern.
+type subsystemFilter struct {
+	rules        map[Subsystem]subsystemRule
+	all          subsystemDecision
+	defaultMatch bool
+}
+
+// Compile a subsystem pattern
+//
+// Returns:
+//     the filter or nil if the pattern matches all subsystems
+func newSubsystemFilter(
+	pattern Subsystem,
+) *subsystemFilter {
+	filter := &subsystemFilter{
+		rules:        make(map[Subsystem]subsystemRule),
+		defaultMatch: true,
+	}
+	empty := true
+	for _, item := range strings.Split(string(pattern), ",") {
+		item = strings.TrimSpace(item)
+		decision := subsystemInclude
+		if strings.HasPrefix(item, "!") {
+			decision = subsystemExclude
+			item = strings.TrimSpace(item[1:])
+		}
+		if item == "" {
+			continue
+		}
+		empty = false
+		if decision == subsystemInclude {
+			filter.defaultMatch = false
+		}
+
+		switch {
+		case item == "*":
+			filter.all = mergeSubsystemDecision(filter.all, decision)
+		case strings.HasSuffix(item, "/*"):
+			node := Subsystem(strings.TrimSuffix(item, "/*"))
+			rule := filter.rules[node]
+			rule.descendants = mergeSubsystemDecision(rule.descendants, decision)
+			filter.rules[node] = rule
+		default:
+			node := Subsystem(item)
+			rule := filter.rules[node]
+			rule.self = mergeSubsystemDecision(rule.self, decision)
+			rule.descendants = mergeSubsystemDecision(rule.descendants, decision)
+			filter.rules[node] = rule
+		}
+	}
+	if empty {
+		return nil
+	}
+	return filter
+}
+
+func mergeSubsystemDecision(
+	current subsystemDecision,
+	decision subsystemDecision,
+) subsystemDecision {
+	if current == subsystemExclude {
+		return current
+	}
+	return decision
+}
+
+// Check whether the filter matches a subsystem
+//
+// Nil filter matches all subsystems.
+func (this *subsystemFilter) match(
+	subsystem Subsystem,
+) bool {
+	if this == nil {
+		return true
+	}
+
+	/* -- the subsystem itself */
+	if rule, exists := this.rules[subsystem]; exists && rule.self != subsystemUnset {
+		return rule.self == subsystemInclude
+	}
+
+	/* -- the closest parent */
+	node := string(subsystem)
+	for {
+		index := strings.LastIndexByte(node, '/')
+		if index < 0 {
+			break
+		}
+		node = node[:index]
+		rule, exists := this.rules[Subsystem(node)]
+		if exists && rule.descendants != subsystemUnset {
+			return rule.descendants == subsystemInclude
+		}
+	}
+
+	if this.all != subsystemUnset {
+		return this.all == subsystemInclude
+	}
+	return this.defaultMatch
+}
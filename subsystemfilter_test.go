@@ -0,0 +1,62 @@
+package goolog2_test
+
+import (
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestSubsystemPatterns(t *testing.T) {
+	t.Parallel()
+
+	subsystems := []Subsystem{"", "db", "db/pool", "db/query", "db/query/slow", "dbx", "http/server"}
+	tests := []struct {
+		pattern  Subsystem
+		expected string
+	}{
+		{"", "1111111"},
+		{"db", "0111100"},
+		{"db/*", "0011100"},
+		{"*", "1111111"},
+		{"db/pool", "0010000"},
+		{"db, http", "0111101"},
+		{"db,!db/query", "0110000"},
+		{"db,!db/query,db/query/slow", "0110100"},
+		{"!db/query", "1110011"},
+		{"!db/*", "1100011"},
+		{"db,!db", "0000000"},
+		{"*,!http", "1111110"},
+	}
+
+	for _, test := range tests {
+		dispatcher := NewLogDispatcher("testlog")
+		logger := &testLogger{}
+		dispatcher.AddLogger("logger", test.pattern, MaskAll, 1, logger)
+		for i, subsystem := range subsystems {
+			DispatcherLogMessage(dispatcher, subsystem, Info, 1, "message")
+			if !logger.Check(test.expected[i] == '1') {
+				t.Errorf("pattern %q, subsystem %q: unexpected result", test.pattern, subsystem)
+			}
+		}
+		dispatcher.Destroy()
+	}
+}
+
+func TestSubsystemPatternChange(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := NewLogDispatcher("testlog")
+	defer dispatcher.Destroy()
+	logger := &testLogger{}
+	dispatcher.AddLogger("logger", "db", MaskAll, 1, logger)
+	dispatcher.SetLoggerSubsystem("logger", "http/*")
+
+	DispatcherLogMessage(dispatcher, "db/pool", Info, 1, "message")
+	if !logger.Check(false) {
+		t.Errorf("the old pattern is still used")
+	}
+	DispatcherLogMessage(dispatcher, "http/server", Info, 1, "message")
+	if !logger.Check(true) {
+		t.Errorf("the new pattern isn't used")
+	}
+}
@@ -0,0 +1,366 @@
+package goolog2
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format of syslog messages
+type SyslogFormat int
+
+const (
+	// The BSD syslog protocol (RFC 3164)
+	SyslogRFC3164 SyslogFormat = iota
+	// The syslog protocol (RFC 5424)
+	SyslogRFC5424
+)
+
+// Syslog facility
+type SyslogFacility int
+
+const (
+	FacilityKern SyslogFacility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFtp
+)
+
+const (
+	FacilityLocal0 SyslogFacility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+/* -- paths of the local syslog socket */
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+const syslogWriteTimeout = 5 * time.Second
+const syslogConnectTimeout = 2 * time.Second
+
+/* -- delay of the next connection attempt after a failure */
+const syslogReconnectDelay = 5 * time.Second
+
+/* -- the message is dropped while the logger waits for the next attempt */
+var errSyslogReconnectDelay = errors.New("goolog2: waiting for reconnection of the syslog")
+
+type syslogLogger struct {
+	errorSink
+	timesrc  TimeSource
+	network  string
+	address  string
+	format   SyslogFormat
+	facility SyslogFacility
+	hostname string
+	pid      string
+	mutex    sync.Mutex
+	conn     net.Conn
+	stream   bool
+	local    bool
+	framing  bool
+
+	/* -- state of the reconnection */
+	connecting     bool
+	destroyed      bool
+	retry          time.Time
+	reconnectDelay time.Duration
+}
+
+// Create new syslog logger
+//
+// The logger accepts line objects. The logging system is used as
+// the APP-NAME (the tag), the subsystem is used as the MSGID.
+//
+// Parameters:
+//     timesrc: a timesource
+//     network: "unixgram", "unix", "udp" or "tcp". If it's empty, the local
+//         syslog socket is used (/dev/log).
+//     address: address of the syslog daemon (path of the unix socket or
+//         host:port)
+//     format: format of the messages
+//     facility: syslog facility of the messages
+// Returns:
+//     the logger
+// Note: if the syslog daemon isn't reachable, the messages are dropped.
+//     The connection is tried again with the first message logged after
+//     a delay (5 seconds of the wall clock). Only the goroutine which
+//     connects waits for the connection (2 seconds at most), messages
+//     of other goroutines are dropped meanwhile.
+func NewSyslogLogger(
+	timesrc TimeSource,
+	network string,
+	address string,
+	format SyslogFormat,
+	facility SyslogFacility,
+) Logger {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	logger := &syslogLogger{
+		timesrc:  timesrc,
+		network:  network,
+		address:  address,
+		format:   format,
+		facility: facility,
+		hostname: hostname,
+		pid:      strconv.Itoa(os.Getpid()),
+		local:    network == "" || strings.HasPrefix(network, "unix"),
+		framing:  strings.HasPrefix(network, "tcp"),
+
+		reconnectDelay: syslogReconnectDelay,
+	}
+
+	/* -- The connection failure isn't fatal. The logger tries to connect
+	   again with the next message. */
+	logger.mutex.Lock()
+	err = logger.connect()
+	logger.mutex.Unlock()
+	logger.reportError(err)
+	return logger
+}
+
+func (this *syslogLogger) Destroy() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.destroyed = true
+	if this.conn != nil {
+		this.conn.Close()
+		this.conn = nil
+	}
+}
+
+func (this *syslogLogger) LogObject(
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	this.logObjectAt(
+		this.timesrc.Now(), system, subsystem, severity, verbosity, object)
+}
+
+func (this *syslogLogger) loggerNow() time.Time {
+	return this.timesrc.Now()
+}
+
+func (this *syslogLogger) logObjectAt(
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	verbosity Verbosity,
+	object interface{},
+) {
+	/* -- the logger supports only line objects */
+	line, ok := object.(LineObject)
+	if !ok {
+		return
+	}
+
+	text := line.GetLogLine()
+	if fieldsObject, ok := object.(FieldsObject); ok {
+		text += formatFields(fieldsObject.GetLogFields())
+	}
+	message := this.formatMessage(now, system, subsystem, severity, text)
+
+	this.mutex.Lock()
+	/* -- The socket could disappear (the daemon has been restarted).
+	   Try to reconnect once. */
+	err := this.writeMessage(message)
+	if err != nil && this.conn != nil {
+		this.conn.Close()
+		this.conn = nil
+		err = this.writeMessage(message)
+	}
+	this.mutex.Unlock()
+
+	/* -- the connection failure has been already reported */
+	if err == errSyslogReconnectDelay {
+		err = nil
+	}
+
+	/* -- the handler is invoked out of the lock, it may log */
+	this.reportError(err)
+}
+
+// Connect to the syslog daemon
+//
+// The function is invoked with the mutex locked. The mutex is unlocked
+// while dialing, hence an unreachable daemon doesn't block other logging
+// goroutines. They drop their messages until the connection is made.
+func (this *syslogLogger) connect() error {
+	if this.connecting || time.Now().Before(this.retry) {
+		return errSyslogReconnectDelay
+	}
+
+	this.connecting = true
+	this.mutex.Unlock()
+	conn, stream, err := this.dial()
+	this.mutex.Lock()
+	this.connecting = false
+
+	if err != nil {
+		this.retry = time.Now().Add(this.reconnectDelay)
+		return err
+	}
+	if this.destroyed {
+		conn.Close()
+		return errSyslogReconnectDelay
+	}
+	this.conn = conn
+	this.stream = stream
+	return nil
+}
+
+// Dial the syslog daemon
+//
+// Returns:
+//     the connection
+//     true if the connection is a stream
+//     an error
+func (this *syslogLogger) dial() (net.Conn, bool, error) {
+	if this.network != "" {
+		conn, err := net.DialTimeout(this.network, this.address, syslogConnectTimeout)
+		if err != nil {
+			return nil, false, err
+		}
+		return conn, this.network == "unix", nil
+	}
+
+	/* -- local syslog */
+	paths := syslogLocalPaths
+	if this.address != "" {
+		paths = []string{this.address}
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range paths {
+			conn, err := net.DialTimeout(network, path, syslogConnectTimeout)
+			if err == nil {
+				return conn, network == "unix", nil
+			}
+		}
+	}
+	return nil, false, errors.New("goolog2: cannot connect to the local syslog")
+}
+
+func (this *syslogLogger) writeMessage(
+	message string,
+) error {
+	if this.conn == nil {
+		if err := this.connect(); err != nil {
+			return err
+		}
+	}
+
+	this.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	var err error
+	switch {
+	case this.framing:
+		/* -- octet counting (RFC 6587) */
+		_, err = fmt.Fprintf(this.conn, "%d %s", len(message), message)
+	case this.stream:
+		_, err = this.conn.Write([]byte(message + "\n"))
+	default:
+		_, err = this.conn.Write([]byte(message))
+	}
+	return err
+}
+
+func (this *syslogLogger) formatMessage(
+	now time.Time,
+	system string,
+	subsystem Subsystem,
+	severity Severity,
+	line string,
+) string {
+	priority := int(this.facility)*8 + syslogSeverity(severity)
+
+	switch this.format {
+	case SyslogRFC5424:
+		return fmt.Sprintf(
+			"<%d>1 %s %s %s %s %s - %s",
+			priority,
+			now.Format("2006-01-02T15:04:05.000000Z07:00"),
+			syslogHeaderField(this.hostname, 255),
+			syslogHeaderField(system, 48),
+			syslogHeaderField(this.pid, 128),
+			syslogHeaderField(string(subsystem), 32),
+			line)
+	default:
+		tag := syslogHeaderField(system, 32)
+		if subsystem != "" {
+			line = "(" + string(subsystem) + "): " + line
+		}
+		if this.local {
+			/* -- the local daemon adds the hostname itself */
+			return fmt.Sprintf(
+				"<%d>%s %s[%s]: %s",
+				priority, now.Format(time.Stamp), tag, this.pid, line)
+		}
+		return fmt.Sprintf(
+			"<%d>%s %s %s[%s]: %s",
+			priority,
+			now.Format(time.Stamp),
+			syslogHeaderField(this.hostname, 255),
+			tag,
+			this.pid,
+			line)
+	}
+}
+
+// Map the severity onto the syslog severity
+func syslogSeverity(
+	severity Severity,
+) int {
+	switch severity {
+	case Critical:
+		return 2 /* -- crit */
+	case Error:
+		return 3 /* -- err */
+	case Warning:
+		return 4 /* -- warning */
+	case Info:
+		return 6 /* -- info */
+	default:
+		return 7 /* -- debug */
+	}
+}
+
+// Make a valid header field: printable ASCII characters of a limited length
+func syslogHeaderField(
+	value string,
+	maxLength int,
+) string {
+	if value == "" {
+		return "-"
+	}
+	builder := &strings.Builder{}
+	for i := 0; i < len(value) && builder.Len() < maxLength; i++ {
+		c := value[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
@@ -0,0 +1,175 @@
+package goolog2_test
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+func syslogTestTime() *MockTimeSource {
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:27")
+	return NewMockTimeSource(now)
+}
+
+func readDatagram(
+	t *testing.T,
+	conn net.PacketConn,
+) string {
+	buffer := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("cannot read the syslog message: %s", err)
+	}
+	return string(buffer[:n])
+}
+
+func TestSyslogLoggerUDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err)
+	}
+	defer listener.Close()
+
+	hostname, _ := os.Hostname()
+	pid := strconv.Itoa(os.Getpid())
+
+	/* -- RFC 5424 */
+	logger := NewSyslogLogger(
+		syslogTestTime(), "udp", listener.LocalAddr().String(),
+		SyslogRFC5424, FacilityLocal0)
+	logger.LogObject("testlog", "db", Error, 1, &lineObject{"connection failed"})
+	expected := "<131>1 2018-08-25T14:02:27.000000Z " + hostname +
+		" testlog " + pid + " db - connection failed"
+	if message := readDatagram(t, listener); message != expected {
+		t.Errorf("unexpected message %q, expected %q", message, expected)
+	}
+	logger.LogObject("testlog", "", Debug, 1, &lineObject{"debug"})
+	expected = "<135>1 2018-08-25T14:02:27.000000Z " + hostname +
+		" testlog " + pid + " - - debug"
+	if message := readDatagram(t, listener); message != expected {
+		t.Errorf("unexpected message %q, expected %q", message, expected)
+	}
+	logger.Destroy()
+
+	/* -- RFC 3164 */
+	logger = NewSyslogLogger(
+		syslogTestTime(), "udp", listener.LocalAddr().String(),
+		SyslogRFC3164, FacilityDaemon)
+	logger.LogObject("testlog", "db", Critical, 1, &lineObject{"crash"})
+	expected = "<26>Aug 25 14:02:27 " + hostname + " testlog[" + pid +
+		"]: (db): crash"
+	if message := readDatagram(t, listener); message != expected {
+		t.Errorf("unexpected message %q, expected %q", message, expected)
+	}
+	logger.Destroy()
+}
+
+func TestSyslogLoggerTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err)
+	}
+	defer listener.Close()
+
+	logger := NewSyslogLogger(
+		syslogTestTime(), "tcp", listener.Addr().String(),
+		SyslogRFC3164, FacilityUser)
+	defer logger.Destroy()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("cannot accept: %s", err)
+	}
+	defer conn.Close()
+
+	logger.LogObject("testlog", "", Warning, 1, &lineObject{"first"})
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"second"})
+
+	/* -- octet counting framing */
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	pid := strconv.Itoa(os.Getpid())
+	for _, message := range []string{"first", "second"} {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			t.Fatalf("cannot read the frame: %s", err)
+		}
+		size, _ := strconv.Atoi(length[:len(length)-1])
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			t.Fatalf("cannot read the frame: %s", err)
+		}
+		tail := " testlog[" + pid + "]: " + message
+		if !strings.HasSuffix(string(frame), tail) {
+			t.Errorf("unexpected message %q", string(frame))
+		}
+	}
+}
+
+func TestSyslogLoggerReconnect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goolog2")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log")
+
+	listener, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+	logger := NewSyslogLogger(
+		syslogTestTime(), "unixgram", path, SyslogRFC3164, FacilityUser)
+	defer logger.Destroy()
+	handler := &collectingErrorHandler{}
+	logger.(ErrorReporter).SetErrorHandler(handler)
+	SetSyslogReconnectDelay(logger, 500*time.Millisecond)
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"first"})
+	expected := "<14>Aug 25 14:02:27 testlog[" + pid + "]: first"
+	if message := readDatagram(t, listener); message != expected {
+		t.Errorf("unexpected message %q, expected %q", message, expected)
+	}
+
+	/* -- the socket disappears, the message is lost */
+	listener.Close()
+	os.Remove(path)
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"lost"})
+
+	/* -- the daemon is back but the reconnection is delayed */
+	listener, err = net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err)
+	}
+	defer listener.Close()
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"delayed"})
+	if errs := handler.Errors(); len(errs) != 1 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	/* -- the delay is measured by the wall clock, not by the frozen mocked time */
+	time.Sleep(600 * time.Millisecond)
+	logger.LogObject("testlog", "", Info, 1, &lineObject{"second"})
+	expected = "<14>Aug 25 14:02:27 testlog[" + pid + "]: second"
+	if message := readDatagram(t, listener); message != expected {
+		t.Errorf("unexpected message %q, expected %q", message, expected)
+	}
+}
+
+type lineObject struct {
+	line string
+}
+
+func (this *lineObject) GetLogLine() string {
+	return this.line
+}
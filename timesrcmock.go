@@ -0,0 +1,77 @@
+package goolog2
+
+import (
+	"sync"
+	"time"
+)
+
+// Mocked time source
+//
+// The time source returns a time set by the user. It's meant for tests
+// of logging into files: if the time source is passed to InitWithTimeSource,
+// every change of the time runs the rotators of the global log scheduled
+// for the new time or earlier and waits until they finish. Hence
+// the rotation of the logging files is deterministic.
+//
+// The time source is safe to be used by concurrent goroutines.
+type MockTimeSource struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// Create new mocked time source
+//
+// Parameters:
+//     now: initial time
+func NewMockTimeSource(
+	now time.Time,
+) *MockTimeSource {
+	return &MockTimeSource{
+		now: now,
+	}
+}
+
+func (this *MockTimeSource) Now() time.Time {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.now
+}
+
+// Set the time and run the due rotators of the global log (if the time
+// source is used by it)
+//
+// Parameters:
+//     now: the new time
+func (this *MockTimeSource) Set(
+	now time.Time,
+) {
+	this.mutex.Lock()
+	this.now = now
+	this.mutex.Unlock()
+	this.afterChange()
+}
+
+// Move the time forward and run the due rotators of the global log (if
+// the time source is used by it)
+//
+// Parameters:
+//     duration: the time shift
+// Returns:
+//     the new time
+func (this *MockTimeSource) Advance(
+	duration time.Duration,
+) time.Time {
+	this.mutex.Lock()
+	this.now = this.now.Add(duration)
+	now := this.now
+	this.mutex.Unlock()
+	this.afterChange()
+	return now
+}
+
+// Run the due rotators if the time source is used by the global log
+func (this *MockTimeSource) afterChange() {
+	if timeSource == TimeSource(this) {
+		AfterChangeMockedTime(true)
+	}
+}
@@ -0,0 +1,64 @@
+package goolog2_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/Staon/goolog2"
+)
+
+type countingRotator struct {
+	interval time.Duration
+	next     time.Time
+	count    int
+}
+
+func (this *countingRotator) NeedRotate(
+	timesrc TimeSource,
+) bool {
+	return true
+}
+
+func (this *countingRotator) Rotate(
+	timesrc TimeSource,
+) {
+	this.count++
+}
+
+func (this *countingRotator) GetNextCheckTime(
+	timesrc TimeSource,
+) time.Time {
+	if this.next.IsZero() {
+		this.next = timesrc.Now()
+	}
+	this.next = this.next.Add(this.interval)
+	return this.next
+}
+
+func TestMockTimeSource(t *testing.T) {
+	now, _ := time.Parse("2006-01-02T15:04:05", "2018-08-25T14:02:00")
+	timesrc := NewMockTimeSource(now)
+	InitWithTimeSource("testlog", timesrc)
+	defer Destroy()
+
+	rotator := &countingRotator{interval: time.Minute}
+	AddLogRotator(rotator)
+
+	/* -- the counter is read without any synchronization, the race
+	   detector verifies the acknowledgement of the rotator */
+	timesrc.Advance(30 * time.Second)
+	if rotator.count != 0 {
+		t.Errorf("the rotator runs too early")
+	}
+	timesrc.Advance(30 * time.Second)
+	if rotator.count != 1 {
+		t.Errorf("the rotator hasn't run: %d", rotator.count)
+	}
+	timesrc.Set(now.Add(5 * time.Minute))
+	if rotator.count != 5 {
+		t.Errorf("the missed checks haven't run: %d", rotator.count)
+	}
+	if !timesrc.Now().Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("unexpected time: %s", timesrc.Now())
+	}
+}
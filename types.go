@@ -1,8 +1,23 @@
 package goolog2
 
-import ()
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Subsystem name
+//
+// The subsystems can be hierarchical, the levels are separated by slashes
+// ("db/pool", "db/query"). Subsystem of a logger is a pattern - comma
+// separated list of items:
+//     db ........ the subsystem db and all nested subsystems
+//     db/* ...... only the nested subsystems of db
+//     * ......... all subsystems
+//     !db/query . exclusion of the subsystem and its nested subsystems
+// The most specific item decides. If the pattern contains only
+// exclusions, all other subsystems are logged.
 type Subsystem string
 
 // Severity of a log message
@@ -16,6 +31,10 @@ const (
 	Debug
 )
 
+// Get the code of the severity shown in the logs
+//
+// Returns:
+//     the upper case name of the severity or "UNKNOWN" for an invalid value
 func (this Severity) Code() string {
 	switch this {
 	case Critical:
@@ -29,8 +48,85 @@ func (this Severity) Code() string {
 	case Debug:
 		return "DEBUG"
 	default:
-		panic("invalid severity code")
+		return "UNKNOWN"
+	}
+}
+
+var severityNames = []struct {
+	severity Severity
+	name     string
+}{
+	{Critical, "critical"},
+	{Error, "error"},
+	{Warning, "warning"},
+	{Info, "info"},
+	{Debug, "debug"},
+}
+
+// Parse a severity
+//
+// Parameters:
+//     text: name of the severity ("critical", "error", "warning", "info"
+//         or "debug"). The case is ignored.
+// Returns:
+//     the severity
+//     an error if the name is unknown
+func ParseSeverity(
+	text string,
+) (Severity, error) {
+	name := strings.TrimSpace(text)
+	for _, item := range severityNames {
+		if strings.EqualFold(item.name, name) {
+			return item.severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q", name)
+}
+
+// Get the lower case name of the severity
+func (this Severity) String() string {
+	for _, item := range severityNames {
+		if item.severity == this {
+			return item.name
+		}
+	}
+	return "Severity(" + strconv.FormatUint(uint64(this), 10) + ")"
+}
+
+// Set the severity from its name (flag.Value interface)
+func (this *Severity) Set(
+	text string,
+) error {
+	severity, err := ParseSeverity(text)
+	if err != nil {
+		return err
 	}
+	*this = severity
+	return nil
+}
+
+// See encoding.TextMarshaler
+func (this Severity) MarshalText() ([]byte, error) {
+	return []byte(this.String()), nil
+}
+
+// See encoding.TextUnmarshaler
+func (this *Severity) UnmarshalText(
+	text []byte,
+) error {
+	return this.Set(string(text))
+}
+
+// Unmarshal the severity from a JSON string or number (see json.Unmarshaler)
+//
+// The numeric form is accepted for compatibility with older documents.
+func (this *Severity) UnmarshalJSON(
+	data []byte,
+) error {
+	return unmarshalJSONNumberOrText(
+		data,
+		func(number uint32) { *this = Severity(number) },
+		this.Set)
 }
 
 // Mask of severities
@@ -46,7 +142,169 @@ const (
 	MaskAll      SeverityMask = SeverityMask(Critical | Error | Warning | Info | Debug)
 )
 
+// Parse a mask of severities
+//
+// Parameters:
+//     text: comma separated list of severity names or the special names
+//         "all", "std" (all severities except debug) and "none". The case
+//         is ignored. An empty string means no severity.
+// Returns:
+//     the mask
+//     an error if a name is unknown
+func ParseSeverityMask(
+	text string,
+) (SeverityMask, error) {
+	var mask SeverityMask
+	for _, item := range strings.Split(text, ",") {
+		name := strings.ToLower(strings.TrimSpace(item))
+		switch name {
+		case "", "none":
+		case "all":
+			mask |= MaskAll
+		case "std":
+			mask |= MaskStd
+		default:
+			severity, err := ParseSeverity(name)
+			if err != nil {
+				return 0, err
+			}
+			mask |= SeverityMask(severity)
+		}
+	}
+	return mask, nil
+}
+
+// Get the mask as a text accepted by ParseSeverityMask
+func (this SeverityMask) String() string {
+	switch this {
+	case 0:
+		return "none"
+	case MaskAll:
+		return "all"
+	case MaskStd:
+		return "std"
+	}
+	var names []string
+	rest := this
+	for _, item := range severityNames {
+		if this&SeverityMask(item.severity) != 0 {
+			names = append(names, item.name)
+			rest &^= SeverityMask(item.severity)
+		}
+	}
+	if rest != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(rest), 16))
+	}
+	return strings.Join(names, ",")
+}
+
+// Set the mask from a text (flag.Value interface)
+func (this *SeverityMask) Set(
+	text string,
+) error {
+	mask, err := ParseSeverityMask(text)
+	if err != nil {
+		return err
+	}
+	*this = mask
+	return nil
+}
+
+// See encoding.TextMarshaler
+func (this SeverityMask) MarshalText() ([]byte, error) {
+	return []byte(this.String()), nil
+}
+
+// See encoding.TextUnmarshaler
+func (this *SeverityMask) UnmarshalText(
+	text []byte,
+) error {
+	return this.Set(string(text))
+}
+
+// Unmarshal the mask from a JSON string or number (see json.Unmarshaler)
+//
+// The numeric form is accepted for compatibility with older documents.
+func (this *SeverityMask) UnmarshalJSON(
+	data []byte,
+) error {
+	return unmarshalJSONNumberOrText(
+		data,
+		func(number uint32) { *this = SeverityMask(number) },
+		this.Set)
+}
+
 // Verbosity of a log message
 //
 // 0 means no logging, a higher number means a higher verbosity level
 type Verbosity uint32
+
+func (this Verbosity) String() string {
+	return strconv.FormatUint(uint64(this), 10)
+}
+
+// Set the verbosity from a decimal number (flag.Value interface)
+func (this *Verbosity) Set(
+	text string,
+) error {
+	value, err := strconv.ParseUint(strings.TrimSpace(text), 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid verbosity %q", text)
+	}
+	*this = Verbosity(value)
+	return nil
+}
+
+// See encoding.TextUnmarshaler
+func (this *Verbosity) UnmarshalText(
+	text []byte,
+) error {
+	return this.Set(string(text))
+}
+
+// Marshal the verbosity as a JSON number (see json.Marshaler)
+func (this Verbosity) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(this), 10), nil
+}
+
+// Unmarshal the verbosity from a JSON number or string (see json.Unmarshaler)
+func (this *Verbosity) UnmarshalJSON(
+	data []byte,
+) error {
+	return unmarshalJSONNumberOrText(
+		data,
+		func(number uint32) { *this = Verbosity(number) },
+		this.Set)
+}
+
+// Unmarshal a JSON value which can be a number or a text
+//
+// The JSON null is ignored like the encoding/json package does.
+//
+// Parameters:
+//     data: the JSON value
+//     setNumber: function storing a number
+//     setText: function parsing a text
+func unmarshalJSONNumberOrText(
+	data []byte,
+	setNumber func(number uint32),
+	setText func(text string) error,
+) error {
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return setText(text)
+	default:
+		var number uint32
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		setNumber(number)
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package goolog2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/Staon/goolog2"
+)
+
+func TestParseSeverityMask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		mask     SeverityMask
+		expected string
+	}{
+		{"all", MaskAll, "all"},
+		{"STD", MaskStd, "std"},
+		{"", 0, "none"},
+		{"none", 0, "none"},
+		{"error, warning", MaskError | MaskWarning, "error,warning"},
+		{"debug,critical", MaskDebug | MaskCritical, "critical,debug"},
+		{"std,debug", MaskAll, "all"},
+	}
+	for _, test := range tests {
+		mask, err := ParseSeverityMask(test.text)
+		if err != nil || mask != test.mask || mask.String() != test.expected {
+			t.Errorf("%q: unexpected mask %s (%v)", test.text, mask, err)
+		}
+	}
+	if _, err := ParseSeverityMask("error,fatal"); err == nil {
+		t.Errorf("unknown severity should fail")
+	}
+	if SeverityMask(1<<7|uint32(MaskInfo)).String() != "info,0x80" {
+		t.Errorf("unexpected text of an invalid mask")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	severity, err := ParseSeverity("Warning")
+	if err != nil || severity != Warning || severity.String() != "warning" {
+		t.Errorf("unexpected severity %s (%v)", severity, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Errorf("unknown severity should fail")
+	}
+	if Severity(1<<7).Code() != "UNKNOWN" || Severity(1<<7).String() != "Severity(128)" {
+		t.Errorf("unexpected text of an invalid severity")
+	}
+}
+
+func TestSeverityText(t *testing.T) {
+	t.Parallel()
+
+	type settings struct {
+		Severity   Severity
+		Severities SeverityMask
+		Verbosity  Verbosity
+	}
+	data, err := json.Marshal(settings{Error, MaskCritical | MaskInfo, 3})
+	if err != nil || string(data) != `{"Severity":"error","Severities":"critical,info","Verbosity":3}` {
+		t.Errorf("unexpected JSON: %s (%v)", data, err)
+	}
+	var decoded settings
+	err = json.Unmarshal(
+		[]byte(`{"Severity":"debug","Severities":"std","Verbosity":4}`), &decoded)
+	if err != nil || decoded != (settings{Debug, MaskStd, 4}) {
+		t.Errorf("unexpected decoded values: %v (%v)", decoded, err)
+	}
+	if json.Unmarshal([]byte(`{"Verbosity":-1}`), &decoded) == nil ||
+		json.Unmarshal([]byte(`{"Verbosity":"x"}`), &decoded) == nil {
+		t.Errorf("invalid verbosity should fail")
+	}
+	if err := json.Unmarshal([]byte(`{"Verbosity":"6"}`), &decoded); err != nil ||
+		decoded.Verbosity != 6 {
+		t.Errorf("unexpected verbosity from a string: %v (%v)", decoded.Verbosity, err)
+	}
+	err = json.Unmarshal([]byte(`{"Severity":8,"Severities":31}`), &decoded)
+	if err != nil || decoded.Severity != Info || decoded.Severities != MaskAll {
+		t.Errorf("unexpected decoded numbers: %v (%v)", decoded, err)
+	}
+	var verbosity Verbosity
+	if err := verbosity.UnmarshalText([]byte("3")); err != nil || verbosity != 3 {
+		t.Errorf("unexpected verbosity from a text: %v (%v)", verbosity, err)
+	}
+}